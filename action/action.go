@@ -1,23 +1,164 @@
 package action
 
 import (
+	"errors"
+	"os"
+
+	"github.com/dyntrait/goctl-swagger/diff"
 	"github.com/dyntrait/goctl-swagger/generate"
+	"github.com/dyntrait/goctl-swagger/lint"
+	"github.com/dyntrait/goctl-swagger/validate"
 	"github.com/urfave/cli/v2"
+	"github.com/zeromicro/go-zero/tools/goctl/api/parser"
 	plugin2 "github.com/zeromicro/go-zero/tools/goctl/plugin"
 )
 
+// Process exit codes, one per error category of the generate, validate, diff and lint packages.
+const (
+	ExitOK = iota
+	ExitFailure
+	ExitBadOption
+	ExitBadInput
+	ExitUnsupportedType
+	ExitWriteFailure
+	ExitInvalidSpec
+	ExitBreakingChange
+	ExitLintFailure
+)
+
 func Generator(ctx *cli.Context) error {
+	closeLog, err := setupLog(ctx)
+	if err != nil {
+		return err
+	}
+	defer closeLog()
+
 	fileName := ctx.String("filename")
+	format := ctx.String("format")
 
 	if len(fileName) == 0 {
 		fileName = "rest.swagger.json"
+		if format == generate.FormatYAML {
+			fileName = "rest.swagger.yaml"
+		}
 	}
 
-	p, err := plugin2.NewPlugin()
+	cfg, err := loadConfig(ctx)
 	if err != nil {
 		return err
 	}
-	basepath := ctx.String("basepath")
-	host := ctx.String("host")
-	return generate.Do(fileName, host, basepath, p)
+
+	p, err := loadPlugin(ctx)
+	if err != nil {
+		return err
+	}
+	return generate.DoWithOptions(p, generate.Options{
+		Filename:    fileName,
+		Host:        ctx.String("host"),
+		BasePath:    ctx.String("basepath"),
+		Spec:        ctx.String("spec"),
+		Format:      format,
+		Embed:       ctx.String("embed"),
+		OperationID: ctx.String("operation-id"),
+		Prune:       ctx.Bool("prune"),
+		Config:      cfg,
+	})
+}
+
+// loadConfig reads the file given by -config, nil without it.
+func loadConfig(ctx *cli.Context) (*generate.Config, error) {
+	configFile := ctx.String("config")
+	if len(configFile) == 0 {
+		return nil, nil
+	}
+	return generate.LoadConfig(configFile)
+}
+
+// loadPlugin reads the goctl plugin payload from stdin, or parses the .api
+// file given by -api so the generator can run without goctl.
+func loadPlugin(ctx *cli.Context) (*plugin2.Plugin, error) {
+	apiFile := ctx.String("api")
+	if len(apiFile) == 0 {
+		p, err := plugin2.NewPlugin()
+		if err != nil {
+			return nil, &generate.InputError{Source: "goctl plugin payload", Err: err}
+		}
+		return p, nil
+	}
+
+	api, err := parser.Parse(apiFile)
+	if err != nil {
+		return nil, &generate.InputError{Source: apiFile, Err: err}
+	}
+
+	dir := ctx.String("dir")
+	if len(dir) == 0 {
+		dir = "."
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, &generate.WriteError{Path: dir, Err: err}
+	}
+
+	return &plugin2.Plugin{
+		Api:         api,
+		ApiFilePath: apiFile,
+		Dir:         dir,
+	}, nil
+}
+
+// setupLog applies the -log-level and -log-file flags to the generator diagnostics.
+func setupLog(ctx *cli.Context) (func(), error) {
+	if err := generate.SetLogLevel(ctx.String("log-level")); err != nil {
+		return nil, err
+	}
+
+	logFile := ctx.String("log-file")
+	if len(logFile) == 0 {
+		return func() {}, nil
+	}
+
+	f, err := os.OpenFile(logFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
+	if err != nil {
+		return nil, &generate.WriteError{Path: logFile, Err: err}
+	}
+	generate.SetLogOutput(f)
+
+	return func() {
+		generate.SetLogOutput(os.Stderr)
+		_ = f.Close()
+	}, nil
+}
+
+// ExitCode maps an error returned by a command to the process exit code.
+func ExitCode(err error) int {
+	var (
+		optionErr *generate.OptionError
+		inputErr  *generate.InputError
+		typeErr   *generate.UnsupportedTypeError
+		writeErr  *generate.WriteError
+		specErr   *validate.Error
+		diffErr   *diff.Error
+		lintErr   *lint.Error
+	)
+
+	switch {
+	case err == nil:
+		return ExitOK
+	case errors.As(err, &optionErr):
+		return ExitBadOption
+	case errors.As(err, &inputErr):
+		return ExitBadInput
+	case errors.As(err, &typeErr):
+		return ExitUnsupportedType
+	case errors.As(err, &writeErr):
+		return ExitWriteFailure
+	case errors.As(err, &specErr):
+		return ExitInvalidSpec
+	case errors.As(err, &diffErr):
+		return ExitBreakingChange
+	case errors.As(err, &lintErr):
+		return ExitLintFailure
+	default:
+		return ExitFailure
+	}
 }
@@ -0,0 +1,35 @@
+package action
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/dyntrait/goctl-swagger/diff"
+	"github.com/dyntrait/goctl-swagger/generate"
+	"github.com/dyntrait/goctl-swagger/lint"
+	"github.com/dyntrait/goctl-swagger/validate"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{nil, ExitOK},
+		{errors.New("boom"), ExitFailure},
+		{&generate.OptionError{Option: "operation-id", Value: "{foo}"}, ExitBadOption},
+		{fmt.Errorf("render: %w", &generate.OptionError{Option: "spec"}), ExitBadOption},
+		{&generate.InputError{Source: "user.api", Err: errors.New("syntax error")}, ExitBadInput},
+		{&generate.UnsupportedTypeError{}, ExitUnsupportedType},
+		{&generate.WriteError{Path: "user.json", Err: errors.New("read-only")}, ExitWriteFailure},
+		{&validate.Error{Path: "user.json"}, ExitInvalidSpec},
+		{&diff.Error{Report: &diff.Report{Breaking: 2}}, ExitBreakingChange},
+		{&lint.Error{Errors: 1}, ExitLintFailure},
+	}
+	for _, tt := range tests {
+		if got := ExitCode(tt.err); got != tt.want {
+			t.Errorf("ExitCode(%v) = %d, want %d", tt.err, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package action
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/dyntrait/goctl-swagger/diff"
+	"github.com/dyntrait/goctl-swagger/generate"
+	"github.com/urfave/cli/v2"
+)
+
+// Differ compares the two versions given as arguments and prints the
+// changes, it fails with ExitBreakingChange if the new version breaks clients.
+func Differ(ctx *cli.Context) error {
+	if ctx.NArg() != 2 {
+		return &generate.InputError{Err: errors.New("expected the old and the new version")}
+	}
+
+	closeLog, err := setupLog(ctx)
+	if err != nil {
+		return err
+	}
+	defer closeLog()
+
+	report, err := diff.Files(ctx.Args().Get(0), ctx.Args().Get(1))
+	if report == nil {
+		return err
+	}
+
+	switch format := ctx.String("format"); format {
+	case "", "text":
+		for _, change := range report.Changes {
+			fmt.Fprintln(ctx.App.Writer, change)
+		}
+	case generate.FormatJSON:
+		enc := json.NewEncoder(ctx.App.Writer)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			return err
+		}
+	default:
+		return &generate.OptionError{Option: "format", Value: format, Expected: []string{"text", generate.FormatJSON}}
+	}
+
+	return err
+}
@@ -0,0 +1,55 @@
+package action
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/dyntrait/goctl-swagger/generate"
+	"github.com/dyntrait/goctl-swagger/lint"
+	"github.com/urfave/cli/v2"
+	"github.com/zeromicro/go-zero/tools/goctl/api/parser"
+)
+
+// Linter checks the .api file given as argument and prints the findings, it
+// fails with ExitLintFailure if a finding has severity error.
+func Linter(ctx *cli.Context) error {
+	if ctx.NArg() != 1 {
+		return &generate.InputError{Err: errors.New("expected an .api file")}
+	}
+
+	var cfg *lint.Config
+	if configFile := ctx.String("config"); len(configFile) > 0 {
+		var err error
+		if cfg, err = lint.LoadConfig(configFile); err != nil {
+			return err
+		}
+	}
+
+	apiFile := ctx.Args().First()
+	api, err := parser.Parse(apiFile)
+	if err != nil {
+		return &generate.InputError{Source: apiFile, Err: err}
+	}
+
+	findings, err := lint.Check(api, cfg)
+	switch format := ctx.String("format"); format {
+	case "", "text":
+		for _, f := range findings {
+			fmt.Fprintln(ctx.App.Writer, f)
+		}
+	case generate.FormatJSON:
+		if findings == nil {
+			findings = []lint.Finding{}
+		}
+		enc := json.NewEncoder(ctx.App.Writer)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(findings); err != nil {
+			return err
+		}
+	default:
+		return &generate.OptionError{Option: "format", Value: format, Expected: []string{"text", generate.FormatJSON}}
+	}
+
+	return err
+}
@@ -0,0 +1,61 @@
+package action
+
+import (
+	"github.com/dyntrait/goctl-swagger/generate"
+	"github.com/urfave/cli/v2"
+	plugin2 "github.com/zeromicro/go-zero/tools/goctl/plugin"
+)
+
+// PostmanGenerator writes the Postman v2.1 collection of the api.
+func PostmanGenerator(ctx *cli.Context) error {
+	return generateWith(ctx, "rest.postman_collection.json", generate.DoPostman)
+}
+
+// HTTPGenerator writes the .http request files of the api and their environment file.
+func HTTPGenerator(ctx *cli.Context) error {
+	return generateWith(ctx, "http-client.env.json", generate.DoHTTP)
+}
+
+// MarkdownGenerator writes the Markdown reference of the api.
+func MarkdownGenerator(ctx *cli.Context) error {
+	return generateWith(ctx, "rest.md", generate.DoMarkdown)
+}
+
+// HTMLGenerator writes the offline HTML documentation of the api.
+func HTMLGenerator(ctx *cli.Context) error {
+	return generateWith(ctx, "rest.html", generate.DoHTML)
+}
+
+// generateWith runs do with the options of the flags the generating commands
+// share, defaultFilename is used when -filename is omitted.
+func generateWith(ctx *cli.Context, defaultFilename string, do func(*plugin2.Plugin, generate.Options) error) error {
+	closeLog, err := setupLog(ctx)
+	if err != nil {
+		return err
+	}
+	defer closeLog()
+
+	fileName := ctx.String("filename")
+	if len(fileName) == 0 {
+		fileName = defaultFilename
+	}
+
+	cfg, err := loadConfig(ctx)
+	if err != nil {
+		return err
+	}
+
+	p, err := loadPlugin(ctx)
+	if err != nil {
+		return err
+	}
+	return do(p, generate.Options{
+		Filename:    fileName,
+		Host:        ctx.String("host"),
+		BasePath:    ctx.String("basepath"),
+		Embed:       ctx.String("embed"),
+		OperationID: ctx.String("operation-id"),
+		Prune:       ctx.Bool("prune"),
+		Config:      cfg,
+	})
+}
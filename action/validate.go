@@ -0,0 +1,39 @@
+package action
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dyntrait/goctl-swagger/generate"
+	"github.com/dyntrait/goctl-swagger/validate"
+	"github.com/urfave/cli/v2"
+)
+
+// Validator checks the documents given as arguments and prints one line per
+// problem, it fails with ExitInvalidSpec if any document is invalid.
+func Validator(ctx *cli.Context) error {
+	if ctx.NArg() == 0 {
+		return &generate.InputError{Err: errors.New("no document to validate")}
+	}
+
+	var invalid error
+	for _, path := range ctx.Args().Slice() {
+		err := validate.File(path)
+		var specErr *validate.Error
+		if !errors.As(err, &specErr) {
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		for _, problem := range specErr.Problems {
+			fmt.Fprintf(ctx.App.Writer, "%s%s\n", path, problem)
+		}
+		if invalid == nil {
+			invalid = err
+		}
+	}
+
+	return invalid
+}
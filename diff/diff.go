@@ -0,0 +1,338 @@
+// Package diff compares two versions of an API, given as Swagger 2.0 or
+// OpenAPI 3.x documents or as .api files, and classifies the changes by
+// whether they break existing clients.
+package diff
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/dyntrait/goctl-swagger/generate"
+	"github.com/dyntrait/goctl-swagger/validate"
+	"github.com/zeromicro/go-zero/tools/goctl/api/parser"
+	"github.com/zeromicro/go-zero/tools/goctl/plugin"
+)
+
+// Kinds of changes reported in Change.Kind.
+const (
+	KindPathAdded         = "path-added"
+	KindPathRemoved       = "path-removed"
+	KindOperationAdded    = "operation-added"
+	KindOperationRemoved  = "operation-removed"
+	KindParameterAdded    = "parameter-added"
+	KindParameterRemoved  = "parameter-removed"
+	KindParameterRequired = "parameter-required"
+	KindResponseRemoved   = "response-removed"
+	KindFieldAdded        = "field-added"
+	KindFieldRemoved      = "field-removed"
+	KindFieldRequired     = "field-required"
+	KindTypeChanged       = "type-changed"
+	KindEnumNarrowed      = "enum-narrowed"
+)
+
+// Change is a difference between the old and the new version.
+type Change struct {
+	Kind     string `json:"kind"`
+	Breaking bool   `json:"breaking"`
+	// Operation is the method and path, such as GET /api/user/{id}.
+	Operation string `json:"operation,omitempty"`
+	// Location is the parameter or field changed, such as response 200 body.name.
+	Location string `json:"location,omitempty"`
+	Message  string `json:"message"`
+}
+
+func (c Change) String() string {
+	severity := "info"
+	if c.Breaking {
+		severity = "BREAKING"
+	}
+
+	where := c.Operation
+	if len(c.Location) > 0 {
+		where += " " + c.Location
+	}
+	return fmt.Sprintf("%s %s %s: %s", severity, c.Kind, where, c.Message)
+}
+
+// Report lists the changes from Old to New.
+type Report struct {
+	Old      string   `json:"old"`
+	New      string   `json:"new"`
+	Breaking int      `json:"breaking"`
+	Changes  []Change `json:"changes"`
+}
+
+// Error is returned by Files when the new version breaks clients.
+type Error struct {
+	Report *Report
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%d breaking change(s) from %s to %s", e.Report.Breaking, e.Report.Old, e.Report.New)
+}
+
+// Files compares the versions at oldPath and newPath. The report is returned
+// along with an *Error if there are breaking changes.
+func Files(oldPath, newPath string) (*Report, error) {
+	oldDoc, err := Load(oldPath)
+	if err != nil {
+		return nil, err
+	}
+	newDoc, err := Load(newPath)
+	if err != nil {
+		return nil, err
+	}
+
+	report := Compare(oldDoc, newDoc)
+	report.Old, report.New = oldPath, newPath
+	if report.Breaking > 0 {
+		return report, &Error{Report: report}
+	}
+	return report, nil
+}
+
+// Load reads a JSON or YAML document, or generates the Swagger 2.0 document
+// of an .api file.
+func Load(path string) (map[string]interface{}, error) {
+	var data []byte
+	if strings.EqualFold(filepath.Ext(path), ".api") {
+		api, err := parser.Parse(path)
+		if err != nil {
+			return nil, &generate.InputError{Source: path, Err: err}
+		}
+		data, err = generate.Render(&plugin.Plugin{Api: api, ApiFilePath: path}, generate.Options{})
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		var err error
+		if data, err = ioutil.ReadFile(path); err != nil {
+			return nil, &generate.InputError{Source: path, Err: err}
+		}
+	}
+
+	doc, err := validate.Decode(data)
+	if err != nil {
+		return nil, &generate.InputError{Source: path, Err: err}
+	}
+	root, ok := doc.(map[string]interface{})
+	if !ok {
+		return nil, &generate.InputError{Source: path, Err: fmt.Errorf("document is not an object")}
+	}
+	return root, nil
+}
+
+// Compare returns the changes from the old to the new document.
+func Compare(oldDoc, newDoc map[string]interface{}) *Report {
+	d := &differ{oldRoot: oldDoc, newRoot: newDoc}
+
+	oldPaths, newPaths := pathsOf(oldDoc), pathsOf(newDoc)
+	for _, key := range validate.SortedKeys(oldPaths) {
+		oldItem := oldPaths[key]
+		newItem, ok := newPaths[key]
+		if !ok {
+			d.add(Change{Kind: KindPathRemoved, Breaking: true, Operation: oldItem.path, Message: "path removed"})
+			continue
+		}
+
+		for _, method := range validate.OperationMethods {
+			oldOp, newOp := oldItem.operations[method], newItem.operations[method]
+			d.op = strings.ToUpper(method) + " " + newItem.path
+			switch {
+			case oldOp == nil && newOp == nil:
+			case newOp == nil:
+				d.op = strings.ToUpper(method) + " " + oldItem.path
+				d.add(Change{Kind: KindOperationRemoved, Breaking: true, Message: "operation removed"})
+			case oldOp == nil:
+				d.add(Change{Kind: KindOperationAdded, Message: "operation added"})
+			default:
+				d.compareOperation(oldOp, newOp)
+			}
+		}
+	}
+	for _, key := range validate.SortedKeys(newPaths) {
+		if _, ok := oldPaths[key]; !ok {
+			d.add(Change{Kind: KindPathAdded, Operation: newPaths[key].path, Message: "path added"})
+		}
+	}
+
+	report := &Report{Changes: d.changes}
+	if report.Changes == nil {
+		report.Changes = []Change{}
+	}
+	for _, c := range report.Changes {
+		if c.Breaking {
+			report.Breaking++
+		}
+	}
+	return report
+}
+
+var pathParamPattern = regexp.MustCompile(`\{[^{}/]*\}`)
+
+type pathItem struct {
+	path       string
+	operations map[string]*operation
+}
+
+// operation is the version independent view of a 2.0 or 3.x operation.
+type operation struct {
+	params    map[string]parameter
+	body      map[string]interface{}
+	responses map[string]map[string]interface{}
+}
+
+type parameter struct {
+	name, in string
+	required bool
+	schema   map[string]interface{}
+}
+
+// pathsOf indexes the path items by their path with the parameter names left
+// out, renaming /user/{id} to /user/{uid} does not change the api.
+func pathsOf(root map[string]interface{}) map[string]pathItem {
+	ret := map[string]pathItem{}
+	paths, _ := root["paths"].(map[string]interface{})
+	for path, v := range paths {
+		item, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		p := pathItem{path: path, operations: map[string]*operation{}}
+		for _, method := range validate.OperationMethods {
+			if op, ok := item[method].(map[string]interface{}); ok {
+				p.operations[method] = operationOf(root, path, item, op)
+			}
+		}
+		ret[pathParamPattern.ReplaceAllString(path, "{}")] = p
+	}
+	return ret
+}
+
+func operationOf(root map[string]interface{}, path string, item, op map[string]interface{}) *operation {
+	ret := &operation{params: map[string]parameter{}, responses: map[string]map[string]interface{}{}}
+
+	var form map[string]interface{}
+	for _, params := range []interface{}{item["parameters"], op["parameters"]} {
+		list, _ := params.([]interface{})
+		for _, v := range list {
+			p := resolve(root, v)
+			name, _ := p["name"].(string)
+			in, _ := p["in"].(string)
+			if in == "body" {
+				// swagger 2.0
+				ret.body = object(p["schema"])
+				continue
+			}
+
+			required, _ := p["required"].(bool)
+			if in == "formData" {
+				// swagger 2.0, compared as the form object 3.x has as request body
+				form = addFormProperty(form, name, p, required)
+				continue
+			}
+
+			schema := p
+			if s, ok := p["schema"].(map[string]interface{}); ok && p["type"] == nil {
+				// openapi 3.x
+				schema = s
+			}
+			// path parameters are matched by position, their names do not matter
+			key := in + " " + name
+			if i := templateIndex(path, name); in == "path" && i >= 0 {
+				key = in + " " + strconv.Itoa(i)
+			}
+			ret.params[key] = parameter{name: name, in: in, required: required, schema: schema}
+		}
+	}
+
+	if form != nil {
+		ret.body = form
+	}
+	if body := resolve(root, op["requestBody"]); body != nil {
+		ret.body = mediaSchema(body["content"])
+	}
+
+	responses, _ := op["responses"].(map[string]interface{})
+	for code, v := range responses {
+		resp := resolve(root, v)
+		if schema := object(resp["schema"]); schema != nil {
+			ret.responses[code] = schema
+		} else if schema := mediaSchema(resp["content"]); schema != nil {
+			ret.responses[code] = schema
+		}
+	}
+	return ret
+}
+
+// addFormProperty adds the formData parameter p to the object schema form,
+// creating it if nil. Files are binary strings as in 3.x.
+func addFormProperty(form map[string]interface{}, name string, p map[string]interface{}, required bool) map[string]interface{} {
+	if form == nil {
+		form = map[string]interface{}{"type": "object", "properties": map[string]interface{}{}}
+	}
+
+	prop := map[string]interface{}{}
+	for key, value := range p {
+		prop[key] = value
+	}
+	if prop["type"] == "file" {
+		prop["type"], prop["format"] = "string", "binary"
+	}
+	form["properties"].(map[string]interface{})[name] = prop
+
+	if required {
+		list, _ := form["required"].([]interface{})
+		form["required"] = append(list, name)
+	}
+	return form
+}
+
+// mediaSchema returns the json schema of a 3.x content object, or the first one.
+func mediaSchema(v interface{}) map[string]interface{} {
+	content, _ := v.(map[string]interface{})
+	if media, ok := content["application/json"].(map[string]interface{}); ok {
+		return object(media["schema"])
+	}
+	for _, mediaType := range validate.SortedKeys(content) {
+		if media, ok := content[mediaType].(map[string]interface{}); ok {
+			return object(media["schema"])
+		}
+	}
+	return nil
+}
+
+// templateIndex is the position of {name} among the templates of path, -1 if absent.
+func templateIndex(path, name string) int {
+	for i, template := range pathParamPattern.FindAllString(path, -1) {
+		if template == "{"+name+"}" {
+			return i
+		}
+	}
+	return -1
+}
+
+func object(v interface{}) map[string]interface{} {
+	m, _ := v.(map[string]interface{})
+	return m
+}
+
+// resolve follows the local $ref of v, if any.
+func resolve(root map[string]interface{}, v interface{}) map[string]interface{} {
+	m := object(v)
+	for i := 0; i < 32 && m != nil; i++ {
+		ref, ok := m["$ref"].(string)
+		if !ok || !strings.HasPrefix(ref, "#/") {
+			return m
+		}
+
+		node, _ := validate.Resolve(root, ref)
+		m = object(node)
+	}
+	return m
+}
@@ -0,0 +1,65 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/dyntrait/goctl-swagger/generate"
+	"github.com/dyntrait/goctl-swagger/validate"
+	"github.com/zeromicro/go-zero/tools/goctl/api/parser"
+	"github.com/zeromicro/go-zero/tools/goctl/plugin"
+)
+
+// render generates the document of an .api file of the tests directory.
+func render(t *testing.T, name, spec string) map[string]interface{} {
+	t.Helper()
+	file := "../tests/" + name
+	api, err := parser.Parse(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := generate.Render(&plugin.Plugin{Api: api, ApiFilePath: file}, generate.Options{Spec: spec})
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := validate.Decode(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return doc.(map[string]interface{})
+}
+
+func TestCompareSpecVersions(t *testing.T) {
+	specs := []string{generate.SpecSwagger2, generate.SpecOpenAPI3, generate.SpecOpenAPI31}
+	for _, name := range []string{"user.api", "embed.api", "upload.api"} {
+		for _, oldSpec := range specs {
+			for _, newSpec := range specs {
+				report := Compare(render(t, name, oldSpec), render(t, name, newSpec))
+				if len(report.Changes) > 0 {
+					t.Errorf("%s %s -> %s: %v", name, oldSpec, newSpec, report.Changes)
+				}
+			}
+		}
+	}
+}
+
+func TestCompareFormParameters(t *testing.T) {
+	oldDoc := render(t, "upload.api", generate.SpecSwagger2)
+	newDoc := render(t, "upload.api", generate.SpecOpenAPI3)
+
+	// kind is optional in the 3.0 document and now required in the 2.0 one
+	params := oldDoc["paths"].(map[string]interface{})["/upload"].(map[string]interface{})["post"].(map[string]interface{})["parameters"].([]interface{})
+	for _, p := range params {
+		if p := p.(map[string]interface{}); p["name"] == "kind" {
+			p["required"] = true
+		}
+	}
+
+	want := Change{Kind: KindFieldRequired, Breaking: true, Operation: "POST /upload", Location: "request body.kind", Message: "field became required"}
+	report := Compare(newDoc, oldDoc)
+	if len(report.Changes) != 1 || report.Changes[0] != want {
+		t.Errorf("changes = %v, want %v", report.Changes, want)
+	}
+	if report := Compare(oldDoc, newDoc); report.Breaking != 0 {
+		t.Errorf("relaxing a form parameter is breaking: %v", report.Changes)
+	}
+}
@@ -0,0 +1,242 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dyntrait/goctl-swagger/validate"
+)
+
+// differ collects the changes of the operation op.
+type differ struct {
+	oldRoot, newRoot map[string]interface{}
+	op               string
+	changes          []Change
+	// seen stops on recursive definitions
+	seen map[string]bool
+}
+
+func (d *differ) add(c Change) {
+	if len(c.Operation) == 0 {
+		c.Operation = d.op
+	}
+	d.changes = append(d.changes, c)
+}
+
+func (d *differ) compareOperation(oldOp, newOp *operation) {
+	for _, key := range validate.SortedKeys(newOp.params) {
+		newParam := newOp.params[key]
+		location := fmt.Sprintf("parameter %s in %s", newParam.name, newParam.in)
+		oldParam, ok := oldOp.params[key]
+		switch {
+		case !ok && newParam.required:
+			d.add(Change{Kind: KindParameterRequired, Breaking: true, Location: location, Message: "required parameter added"})
+		case !ok:
+			d.add(Change{Kind: KindParameterAdded, Location: location, Message: "optional parameter added"})
+		default:
+			if !oldParam.required && newParam.required {
+				d.add(Change{Kind: KindParameterRequired, Breaking: true, Location: location, Message: "parameter became required"})
+			}
+			d.compareSchema(oldParam.schema, newParam.schema, location, true)
+		}
+	}
+	for _, key := range validate.SortedKeys(oldOp.params) {
+		if _, ok := newOp.params[key]; !ok {
+			oldParam := oldOp.params[key]
+			d.add(Change{
+				Kind:     KindParameterRemoved,
+				Location: fmt.Sprintf("parameter %s in %s", oldParam.name, oldParam.in),
+				Message:  "parameter removed",
+			})
+		}
+	}
+
+	if oldOp.body != nil || newOp.body != nil {
+		d.compareSchema(oldOp.body, newOp.body, "request body", true)
+	}
+
+	for _, code := range validate.SortedKeys(oldOp.responses) {
+		location := "response " + code + " body"
+		newSchema, ok := newOp.responses[code]
+		if !ok {
+			d.add(Change{Kind: KindResponseRemoved, Breaking: true, Location: location, Message: "response body removed"})
+			continue
+		}
+		d.compareSchema(oldOp.responses[code], newSchema, location, false)
+	}
+}
+
+// compareSchema reports the changes of a schema sent by clients, request is
+// true, or received by them. Missing schemas are empty ones.
+func (d *differ) compareSchema(oldSchema, newSchema map[string]interface{}, location string, request bool) {
+	key := fmt.Sprintf("%s|%s|%v", refOf(oldSchema), refOf(newSchema), request)
+	if len(refOf(oldSchema)) > 0 && len(refOf(newSchema)) > 0 {
+		if d.seen == nil {
+			d.seen = map[string]bool{}
+		}
+		if d.seen[key] {
+			return
+		}
+		d.seen[key] = true
+		defer delete(d.seen, key)
+	}
+
+	oldView := viewOf(d.oldRoot, oldSchema)
+	newView := viewOf(d.newRoot, newSchema)
+
+	if len(oldView.typ) > 0 && len(newView.typ) > 0 && (oldView.typ != newView.typ || oldView.format != newView.format) {
+		d.add(Change{
+			Kind:     KindTypeChanged,
+			Breaking: true,
+			Location: location,
+			Message:  fmt.Sprintf("type changed from %s to %s", typeName(oldView), typeName(newView)),
+		})
+		return
+	}
+
+	if request && len(newView.enum) > 0 {
+		var removed []string
+		for _, value := range oldView.enum {
+			if !contains(newView.enum, value) {
+				removed = append(removed, value)
+			}
+		}
+		switch {
+		case len(oldView.enum) == 0:
+			d.add(Change{Kind: KindEnumNarrowed, Breaking: true, Location: location, Message: "values restricted to " + strings.Join(newView.enum, ", ")})
+		case len(removed) > 0:
+			d.add(Change{Kind: KindEnumNarrowed, Breaking: true, Location: location, Message: "values removed: " + strings.Join(removed, ", ")})
+		}
+	}
+
+	for _, name := range validate.SortedKeys(oldView.properties) {
+		field := location + "." + name
+		newProp, ok := newView.properties[name]
+		switch {
+		case !ok && request:
+			d.add(Change{Kind: KindFieldRemoved, Location: field, Message: "field removed"})
+		case !ok:
+			d.add(Change{Kind: KindFieldRemoved, Breaking: true, Location: field, Message: "response field removed"})
+		default:
+			if request && !oldView.required[name] && newView.required[name] {
+				d.add(Change{Kind: KindFieldRequired, Breaking: true, Location: field, Message: "field became required"})
+			}
+			d.compareSchema(oldView.properties[name], newProp, field, request)
+		}
+	}
+	for _, name := range validate.SortedKeys(newView.properties) {
+		if _, ok := oldView.properties[name]; ok {
+			continue
+		}
+		field := location + "." + name
+		if request && newView.required[name] {
+			d.add(Change{Kind: KindFieldRequired, Breaking: true, Location: field, Message: "required field added"})
+			continue
+		}
+		d.add(Change{Kind: KindFieldAdded, Location: field, Message: "field added"})
+	}
+
+	if oldView.items != nil && newView.items != nil {
+		d.compareSchema(oldView.items, newView.items, location+"[]", request)
+	}
+	if oldView.additional != nil && newView.additional != nil {
+		d.compareSchema(oldView.additional, newView.additional, location+"{}", request)
+	}
+}
+
+// schemaView is a schema with its references resolved and allOf merged.
+type schemaView struct {
+	typ, format string
+	enum        []string
+	properties  map[string]map[string]interface{}
+	required    map[string]bool
+	items       map[string]interface{}
+	additional  map[string]interface{}
+}
+
+func viewOf(root, schema map[string]interface{}) schemaView {
+	view := schemaView{properties: map[string]map[string]interface{}{}, required: map[string]bool{}}
+	var merge func(schema map[string]interface{}, depth int)
+	merge = func(schema map[string]interface{}, depth int) {
+		schema = resolve(root, schema)
+		if schema == nil || depth > 16 {
+			return
+		}
+
+		if typ := typeOf(schema["type"]); len(typ) > 0 {
+			view.typ = typ
+		}
+		if format, ok := schema["format"].(string); ok {
+			view.format = format
+		}
+		if enum, ok := schema["enum"].([]interface{}); ok {
+			for _, value := range enum {
+				view.enum = append(view.enum, fmt.Sprint(value))
+			}
+		}
+		props, _ := schema["properties"].(map[string]interface{})
+		for name, prop := range props {
+			view.properties[name] = object(prop)
+		}
+		required, _ := schema["required"].([]interface{})
+		for _, name := range required {
+			view.required[fmt.Sprint(name)] = true
+		}
+		if items := object(schema["items"]); items != nil {
+			view.items = items
+		}
+		if additional := object(schema["additionalProperties"]); additional != nil {
+			view.additional = additional
+		}
+
+		for _, key := range []string{"allOf", "anyOf"} {
+			subs, _ := schema[key].([]interface{})
+			for _, sub := range subs {
+				merge(object(sub), depth+1)
+			}
+		}
+	}
+	merge(schema, 0)
+
+	if len(view.properties) > 0 && len(view.typ) == 0 {
+		view.typ = "object"
+	}
+	return view
+}
+
+// typeOf returns the type of a schema, the 3.1 type arrays such as
+// ["integer","null"] are reduced to their first type that is not null.
+func typeOf(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case []interface{}:
+		for _, t := range v {
+			if s, ok := t.(string); ok && s != "null" {
+				return s
+			}
+		}
+	}
+	return ""
+}
+
+func typeName(view schemaView) string {
+	if len(view.format) > 0 {
+		return view.typ + "(" + view.format + ")"
+	}
+	return view.typ
+}
+
+func refOf(schema map[string]interface{}) string {
+	ref, _ := schema["$ref"].(string)
+	return ref
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
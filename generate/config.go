@@ -0,0 +1,86 @@
+package generate
+
+import (
+	"encoding/json"
+	"io/ioutil"
+)
+
+// Config is the optional configuration file of the generator, written in YAML or JSON.
+type Config struct {
+	// Envelope wraps the response of every route in a common schema.
+	Envelope *EnvelopeConfig `json:"envelope,omitempty"`
+	// ErrorResponse is the default body of 4xx and 5xx responses.
+	ErrorResponse *ErrorResponseConfig `json:"errorResponse,omitempty"`
+	// SecuritySchemes replace the default apiKey Authorization header scheme,
+	// e.g. oauth2 flows with scopes, basic auth or api keys in the query.
+	SecuritySchemes map[string]swaggerSecuritySchemeObject `json:"securitySchemes,omitempty"`
+	// JWT maps the X of @server(jwt: X) to the security schemes of its routes,
+	// any of the listed requirements grants access.
+	JWT map[string][]SecurityRequirement `json:"jwt,omitempty"`
+	// Middlewares maps the names of @server(middleware: ...) to security
+	// requirements, headers and vendor extensions of the operations.
+	Middlewares map[string]MiddlewareConfig `json:"middlewares,omitempty"`
+	// ExcludePaths are the routes left out of the document, such as those
+	// serving it. Nil means /swagger and /swagger-json, an empty list keeps
+	// every route.
+	ExcludePaths []string `json:"excludePaths,omitempty"`
+}
+
+// defaultExcludePaths are the routes the restui package registers by default.
+var defaultExcludePaths = []string{"/swagger", "/swagger-json"}
+
+// excludePaths returns the configured excluded routes, or the default ones.
+func (c *Config) excludePaths() []string {
+	if c.ExcludePaths == nil {
+		return defaultExcludePaths
+	}
+	return c.ExcludePaths
+}
+
+// EnvelopeConfig describes the response envelope written by a custom httpx
+// response handler, e.g. {code, msg, data}.
+type EnvelopeConfig struct {
+	// Schema is the envelope schema, the schema {"$ref": "$data"} inside it is
+	// replaced with the response type of the route. Empty means {code, msg, data}.
+	Schema *swaggerSchemaObject `json:"schema,omitempty"`
+}
+
+// LoadConfig reads the configuration file at path.
+func LoadConfig(path string) (*Config, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, &InputError{Source: path, Err: err}
+	}
+
+	data, err = yamlToJSON(data)
+	if err != nil {
+		return nil, &InputError{Source: path, Err: err}
+	}
+
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, &InputError{Source: path, Err: err}
+	}
+	if err := cfg.validateSecurity(); err != nil {
+		return nil, &InputError{Source: path, Err: err}
+	}
+	if err := cfg.validateMiddlewares(); err != nil {
+		return nil, &InputError{Source: path, Err: err}
+	}
+	if err := cfg.validateEnvelope(); err != nil {
+		return nil, &InputError{Source: path, Err: err}
+	}
+
+	return &cfg, nil
+}
+
+// ErrorResponseConfig describes the body of error responses, it is used for
+// every 4xx and 5xx @respdoc annotation that does not name a type.
+type ErrorResponseConfig struct {
+	// Type is a type declared in the api file.
+	Type string `json:"type,omitempty"`
+	// Schema is an inline schema, used if Type is empty.
+	Schema *swaggerSchemaObject `json:"schema,omitempty"`
+	// Codes are added to every route, e.g. [400, 500].
+	Codes []int `json:"codes,omitempty"`
+}
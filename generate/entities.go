@@ -3,48 +3,32 @@ package generate
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"reflect"
+	"sort"
 
 	"github.com/grpc-ecosystem/grpc-gateway/protoc-gen-grpc-gateway/descriptor"
 )
 
+// swaggerMapTypes maps the goctl primitive types to their kind, composite types
+// are resolved structurally by schemaOfType.
 var swaggerMapTypes = map[string]reflect.Kind{
-	"string":   reflect.String,
-	"*string":  reflect.String,
-	"int":      reflect.Int,
-	"*int":     reflect.Int,
-	"uint":     reflect.Uint,
-	"*uint":    reflect.Uint,
-	"int8":     reflect.Int8,
-	"*int8":    reflect.Int8,
-	"uint8":    reflect.Uint8,
-	"*uint8":   reflect.Uint8,
-	"int16":    reflect.Int16,
-	"*int16":   reflect.Int16,
-	"uint16":   reflect.Uint16,
-	"*uint16":  reflect.Uint16,
-	"int32":    reflect.Int,
-	"*int32":   reflect.Int,
-	"uint32":   reflect.Int,
-	"*uint32":  reflect.Int,
-	"uint64":   reflect.Int64,
-	"*uint64":  reflect.Int64,
-	"int64":    reflect.Int64,
-	"*int64":   reflect.Int64,
-	"[]string": reflect.Slice,
-	"[]int":    reflect.Slice,
-	"[]int64":  reflect.Slice,
-	"[]int32":  reflect.Slice,
-	"[]uint32": reflect.Slice,
-	"[]uint64": reflect.Slice,
-	"bool":     reflect.Bool,
-	"*bool":    reflect.Bool,
-	"struct":   reflect.Struct,
-	"*struct":  reflect.Struct,
-	"float32":  reflect.Float32,
-	"*float32": reflect.Float32,
-	"float64":  reflect.Float64,
-	"*float64": reflect.Float64,
+	"string":  reflect.String,
+	"int":     reflect.Int,
+	"uint":    reflect.Uint,
+	"int8":    reflect.Int8,
+	"uint8":   reflect.Uint8,
+	"byte":    reflect.Uint8,
+	"int16":   reflect.Int16,
+	"uint16":  reflect.Uint16,
+	"int32":   reflect.Int,
+	"rune":    reflect.Int,
+	"uint32":  reflect.Int,
+	"uint64":  reflect.Int64,
+	"int64":   reflect.Int64,
+	"bool":    reflect.Bool,
+	"float32": reflect.Float32,
+	"float64": reflect.Float64,
 }
 
 // http://swagger.io/specification/#infoObject
@@ -92,6 +76,16 @@ type swaggerObject struct {
 	SecurityDefinitions swaggerSecurityDefinitionsObject    `json:"securityDefinitions,omitempty"`
 	Security            []swaggerSecurityRequirementObject  `json:"security,omitempty"`
 	ExternalDocs        *swaggerExternalDocumentationObject `json:"externalDocs,omitempty"`
+
+	// operations lists the operations in route order, which the paths object
+	// loses, for the outputs other than the specifications.
+	operations []operationRef
+}
+
+// operationRef locates an operation in the paths object.
+type operationRef struct {
+	Path   string
+	Method string
 }
 
 // http://swagger.io/specification/#securityDefinitionsObject
@@ -127,27 +121,93 @@ type swaggerPathItemObject struct {
 	Patch  *swaggerOperationObject `json:"patch,omitempty"`
 }
 
+// operation returns the operation of the lower case method, nil if there is none.
+func (item swaggerPathItemObject) operation(method string) *swaggerOperationObject {
+	switch method {
+	case "get":
+		return item.Get
+	case "post":
+		return item.Post
+	case "put":
+		return item.Put
+	case "delete":
+		return item.Delete
+	case "patch":
+		return item.Patch
+	default:
+		return nil
+	}
+}
+
 // http://swagger.io/specification/#operationObject
 type swaggerOperationObject struct {
 	Summary     string                  `json:"summary,omitempty"`
 	Description string                  `json:"description,omitempty"`
 	OperationID string                  `json:"operationId"`
+	Consumes    []string                `json:"consumes,omitempty"`
 	Responses   swaggerResponsesObject  `json:"responses"`
 	Parameters  swaggerParametersObject `json:"parameters,omitempty"`
-	RequestBody *struct {
-		Content swaggerContentObject `json:"content,omitempty"`
-	} `json:"requestBody,omitempty"`
-	Tags       []string `json:"tags,omitempty"`
-	Deprecated bool     `json:"deprecated,omitempty"`
+	Tags        []string                `json:"tags,omitempty"`
+	Deprecated  bool                    `json:"deprecated,omitempty"`
 
 	Security     *[]swaggerSecurityRequirementObject `json:"security,omitempty"`
 	ExternalDocs *swaggerExternalDocumentationObject `json:"externalDocs,omitempty"`
+
+	// Extensions are the x- vendor extensions, see MarshalJSON.
+	Extensions map[string]interface{} `json:"-"`
 }
 
-type (
-	swaggerParametersObject []swaggerParameterObject
-	swaggerContentObject    map[string]swaggerParametersObject
-)
+func (o *swaggerOperationObject) addExtension(key string, value interface{}) {
+	if o.Extensions == nil {
+		o.Extensions = map[string]interface{}{}
+	}
+	o.Extensions[key] = value
+}
+
+// MarshalJSON appends the vendor extensions to the operation.
+func (o swaggerOperationObject) MarshalJSON() ([]byte, error) {
+	type operation swaggerOperationObject
+	return marshalWithExtensions(operation(o), o.Extensions)
+}
+
+// marshalWithExtensions encodes v, which must encode as an object, followed
+// by the extensions in key order.
+func marshalWithExtensions(v interface{}, extensions map[string]interface{}) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil || len(extensions) == 0 {
+		return data, err
+	}
+
+	keys := make([]string, 0, len(extensions))
+	for key := range extensions {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var buf bytes.Buffer
+	buf.Write(bytes.TrimSuffix(data, []byte("}")))
+	for i, key := range keys {
+		if i != 0 || len(data) > 2 {
+			buf.WriteString(",")
+		}
+		k, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		val, err := json.Marshal(extensions[key])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(k)
+		buf.WriteString(":")
+		buf.Write(val)
+	}
+	buf.WriteString("}")
+
+	return buf.Bytes(), nil
+}
+
+type swaggerParametersObject []swaggerParameterObject
 
 // http://swagger.io/specification/#parameterObject
 type swaggerParameterObject struct {
@@ -161,6 +221,10 @@ type swaggerParameterObject struct {
 	Enum             []string            `json:"enum,omitempty"` //枚举值 [1,2]
 	CollectionFormat string              `json:"collectionFormat,omitempty"`
 	Default          string              `json:"default,omitempty"`
+	Maximum          *float64            `json:"maximum,omitempty"`
+	ExclusiveMaximum bool                `json:"exclusiveMaximum,omitempty"`
+	Minimum          *float64            `json:"minimum,omitempty"`
+	ExclusiveMinimum bool                `json:"exclusiveMinimum,omitempty"`
 	MinItems         *int                `json:"minItems,omitempty"`
 	Example          string              `json:"example,omitempty"`
 
@@ -185,15 +249,16 @@ type schemaCore struct {
 	Default string   `json:"default,omitempty"`
 }
 
-type swaggerItemsObject schemaCore
+// swaggerItemsObject is a full schema, so items can nest arrays, maps and refs.
+type swaggerItemsObject swaggerSchemaObject
 
 // http://swagger.io/specification/#responsesObject
 type swaggerResponsesObject map[string]swaggerResponseObject
 
 // http://swagger.io/specification/#responseObject
 type swaggerResponseObject struct {
-	Description string              `json:"description"`
-	Schema      swaggerSchemaObject `json:"schema"`
+	Description string               `json:"description"`
+	Schema      *swaggerSchemaObject `json:"schema,omitempty"`
 }
 
 type keyVal struct {
@@ -227,12 +292,42 @@ func (op swaggerSchemaObjectProperties) MarshalJSON() ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
+// UnmarshalJSON keeps the order of the properties, it is used for schemas
+// read from the configuration file.
+func (op *swaggerSchemaObjectProperties) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if _, err := dec.Token(); err != nil {
+		return err
+	}
+
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key, ok := tok.(string)
+		if !ok {
+			return fmt.Errorf("invalid property name %v", tok)
+		}
+
+		var value swaggerSchemaObject
+		if err := dec.Decode(&value); err != nil {
+			return err
+		}
+		*op = append(*op, keyVal{Key: key, Value: value})
+	}
+
+	_, err := dec.Token()
+	return err
+}
+
 // http://swagger.io/specification/#schemaObject
 type swaggerSchemaObject struct {
 	schemaCore
 	// Properties can be recursively defined
 	Properties           *swaggerSchemaObjectProperties `json:"properties,omitempty"`
 	AdditionalProperties *swaggerSchemaObject           `json:"additionalProperties,omitempty"`
+	AllOf                []swaggerSchemaObject          `json:"allOf,omitempty"`
 
 	Description string `json:"description,omitempty"`
 	Title       string `json:"title,omitempty"`
@@ -241,9 +336,9 @@ type swaggerSchemaObject struct {
 
 	ReadOnly         bool     `json:"readOnly,omitempty"`
 	MultipleOf       float64  `json:"multipleOf,omitempty"`
-	Maximum          float64  `json:"maximum,omitempty"`
+	Maximum          *float64 `json:"maximum,omitempty"`
 	ExclusiveMaximum bool     `json:"exclusiveMaximum,omitempty"`
-	Minimum          float64  `json:"minimum,omitempty"`
+	Minimum          *float64 `json:"minimum,omitempty"`
 	ExclusiveMinimum bool     `json:"exclusiveMinimum,omitempty"`
 	MaxLength        uint64   `json:"maxLength,omitempty"`
 	MinLength        uint64   `json:"minLength,omitempty"`
@@ -254,6 +349,10 @@ type swaggerSchemaObject struct {
 	MaxProperties    uint64   `json:"maxProperties,omitempty"`
 	MinProperties    uint64   `json:"minProperties,omitempty"`
 	Required         []string `json:"required,omitempty"`
+
+	// Nullable is set for pointer members. Swagger 2.0 cannot express it,
+	// only the OpenAPI 3.x outputs render it.
+	Nullable bool `json:"-"`
 }
 
 // http://swagger.io/specification/#definitionsObject
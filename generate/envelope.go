@@ -0,0 +1,174 @@
+package generate
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/zeromicro/go-zero/tools/goctl/api/spec"
+)
+
+const (
+	// envelopeDataRef marks where the response type goes in the envelope schema.
+	envelopeDataRef = "$data"
+	// envelopeAnnotation opts a group out with @server(envelope: false).
+	envelopeAnnotation = "envelope"
+	envelopeSuffix     = "Envelope"
+)
+
+// defaultEnvelopeSchema is the {code, msg, data} body most go-zero services use.
+var defaultEnvelopeSchema = swaggerSchemaObject{
+	schemaCore: schemaCore{Type: "object"},
+	Properties: &swaggerSchemaObjectProperties{
+		{Key: "code", Value: swaggerSchemaObject{schemaCore: schemaCore{Type: "integer", Format: "int32"}}},
+		{Key: "msg", Value: swaggerSchemaObject{schemaCore: schemaCore{Type: "string"}}},
+		{Key: "data", Value: swaggerSchemaObject{schemaCore: schemaCore{Ref: envelopeDataRef}}},
+	},
+	Required: []string{"code", "msg"},
+}
+
+// envelopeEnabled reports whether the responses of group are wrapped.
+func envelopeEnabled(cfg *Config, group spec.Group) bool {
+	if cfg.Envelope == nil {
+		return false
+	}
+
+	switch strings.ToLower(strings.Trim(group.GetAnnotation(envelopeAnnotation), `"`)) {
+	case "false", "off", "none":
+		return false
+	default:
+		return true
+	}
+}
+
+// responseRef returns the reference of the response body of respType in
+// group, recording the envelope definition to render if group is wrapped.
+func responseRef(cfg *Config, group spec.Group, envelopes map[string]string, respType string) string {
+	if !envelopeEnabled(cfg, group) {
+		if len(respType) == 0 {
+			return ""
+		}
+		return swaggerDefinitionRef + respType
+	}
+
+	// 统一响应格式 {code, msg, data}
+	name := envelopeName(respType)
+	envelopes[name] = respType
+	return swaggerDefinitionRef + name
+}
+
+// envelopeName is the definition wrapping respType, Envelope if there is no response type.
+func envelopeName(respType string) string {
+	return respType + envelopeSuffix
+}
+
+// validateEnvelope checks that a custom envelope schema has a place for the
+// response type.
+func (c *Config) validateEnvelope() error {
+	if c.Envelope == nil || c.Envelope.Schema == nil {
+		return nil
+	}
+	if !hasEnvelopeData(*c.Envelope.Schema) {
+		return fmt.Errorf("envelope: schema has no {\"$ref\": %q} placeholder", envelopeDataRef)
+	}
+
+	return nil
+}
+
+// hasEnvelopeData reports whether schema contains the data placeholder.
+func hasEnvelopeData(schema swaggerSchemaObject) bool {
+	if schema.Ref == envelopeDataRef {
+		return true
+	}
+	if schema.Items != nil && hasEnvelopeData(swaggerSchemaObject(*schema.Items)) {
+		return true
+	}
+	if schema.AdditionalProperties != nil && hasEnvelopeData(*schema.AdditionalProperties) {
+		return true
+	}
+	if schema.Properties != nil {
+		for _, kv := range *schema.Properties {
+			if prop, ok := kv.Value.(swaggerSchemaObject); ok && hasEnvelopeData(prop) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// renderEnvelopeDefinitions adds one definition per wrapped response type,
+// envelopes maps the definition name to the response type. A declared type
+// with the name of an envelope definition is an error, it would be replaced.
+func renderEnvelopeDefinitions(d swaggerDefinitionsObject, cfg *EnvelopeConfig, envelopes map[string]string) error {
+	template := defaultEnvelopeSchema
+	if cfg.Schema != nil {
+		template = *cfg.Schema
+	}
+
+	names := make([]string, 0, len(envelopes))
+	for name := range envelopes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if _, ok := d[name]; ok {
+			return &InputError{Err: fmt.Errorf("envelope of %s collides with the declared type %s", envelopes[name], name)}
+		}
+
+		dataRef := ""
+		if respType := envelopes[name]; len(respType) > 0 {
+			dataRef = swaggerDefinitionRef + respType
+		}
+
+		schema, _ := substituteEnvelopeData(template, dataRef)
+		schema.Title = name
+		d[name] = schema
+	}
+
+	return nil
+}
+
+// substituteEnvelopeData copies schema with the data placeholder pointed at
+// dataRef. With an empty dataRef the placeholder property is dropped, keep
+// reports whether schema itself is the placeholder.
+func substituteEnvelopeData(schema swaggerSchemaObject, dataRef string) (ret swaggerSchemaObject, keep bool) {
+	if schema.Ref == envelopeDataRef {
+		schema.Ref = dataRef
+		return schema, len(dataRef) > 0
+	}
+
+	if schema.Items != nil {
+		items, _ := substituteEnvelopeData(swaggerSchemaObject(*schema.Items), dataRef)
+		schema.Items = (*swaggerItemsObject)(&items)
+	}
+
+	if schema.AdditionalProperties != nil {
+		additional, _ := substituteEnvelopeData(*schema.AdditionalProperties, dataRef)
+		schema.AdditionalProperties = &additional
+	}
+
+	if schema.Properties != nil {
+		props := make(swaggerSchemaObjectProperties, 0, len(*schema.Properties))
+		required := append([]string(nil), schema.Required...)
+		for _, kv := range *schema.Properties {
+			prop, ok := kv.Value.(swaggerSchemaObject)
+			if !ok {
+				props = append(props, kv)
+				continue
+			}
+
+			prop, keep := substituteEnvelopeData(prop, dataRef)
+			if !keep {
+				required = del(required, kv.Key)
+				continue
+			}
+			props = append(props, keyVal{Key: kv.Key, Value: prop})
+		}
+		schema.Properties = &props
+		schema.Required = required
+	}
+
+	return schema, true
+}
@@ -0,0 +1,32 @@
+package generate
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEnvelopeSchemaWithoutData(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	config := "envelope:\n  schema:\n    type: object\n    properties:\n      code:\n        type: integer\n"
+	if err := os.WriteFile(path, []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := LoadConfig(path)
+	var inputErr *InputError
+	if !errors.As(err, &inputErr) || !strings.Contains(err.Error(), envelopeDataRef) {
+		t.Errorf("LoadConfig = %v, want an InputError about %s", err, envelopeDataRef)
+	}
+}
+
+func TestEnvelopeCollidesWithDeclaredType(t *testing.T) {
+	cfg := &Config{Envelope: &EnvelopeConfig{}}
+	_, err := build(loadAPI(t, "envelope.api"), Options{Config: cfg})
+	var inputErr *InputError
+	if !errors.As(err, &inputErr) || !strings.Contains(err.Error(), "OrderReplyEnvelope") {
+		t.Errorf("build = %v, want an InputError about OrderReplyEnvelope", err)
+	}
+}
@@ -0,0 +1,65 @@
+package generate
+
+import (
+	"fmt"
+	"strings"
+)
+
+// OptionError reports an option value the generator does not understand.
+type OptionError struct {
+	Option   string
+	Value    string
+	Expected []string
+}
+
+func (e *OptionError) Error() string {
+	return fmt.Sprintf("unsupported %s %q, expected one of %s", e.Option, e.Value, strings.Join(e.Expected, ", "))
+}
+
+// InputError reports a goctl plugin payload or .api file that cannot be used.
+type InputError struct {
+	Source string
+	Err    error
+}
+
+func (e *InputError) Error() string {
+	if len(e.Source) == 0 {
+		return fmt.Sprintf("invalid input: %v", e.Err)
+	}
+	return fmt.Sprintf("invalid input %s: %v", e.Source, e.Err)
+}
+
+func (e *InputError) Unwrap() error {
+	return e.Err
+}
+
+// UnsupportedTypeError reports a member whose type cannot be rendered as a schema.
+type UnsupportedTypeError struct {
+	// Struct is the type declaring the member, empty if unknown.
+	Struct string
+	Member string
+	Type   string
+	Reason string
+}
+
+func (e *UnsupportedTypeError) Error() string {
+	member := e.Member
+	if len(e.Struct) > 0 {
+		member = e.Struct + "." + e.Member
+	}
+	return fmt.Sprintf("unsupported type %s of member %s: %s", e.Type, member, e.Reason)
+}
+
+// WriteError reports a failure to encode or write the generated document.
+type WriteError struct {
+	Path string
+	Err  error
+}
+
+func (e *WriteError) Error() string {
+	return fmt.Sprintf("write %s: %v", e.Path, e.Err)
+}
+
+func (e *WriteError) Unwrap() error {
+	return e.Err
+}
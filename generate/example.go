@@ -0,0 +1,127 @@
+package generate
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// exampleOf synthesizes a sample value of schema, preferring its example,
+// default and first enum value. Refs are resolved in d and a cyclic ref
+// becomes null. Objects keep the order of their properties.
+func exampleOf(schema swaggerSchemaObject, d swaggerDefinitionsObject, seen map[string]bool) interface{} {
+	if len(schema.Ref) > 0 {
+		name := strings.TrimPrefix(schema.Ref, swaggerDefinitionRef)
+		def, ok := d[name]
+		if !ok || seen[name] {
+			return nil
+		}
+		seen[name] = true
+		defer delete(seen, name)
+		return exampleOf(def, d, seen)
+	}
+
+	if len(schema.AllOf) > 0 {
+		merged := swaggerSchemaObjectProperties{}
+		for _, part := range append(schema.AllOf, swaggerSchemaObject{Properties: schema.Properties}) {
+			if props, ok := exampleOf(part, d, seen).(swaggerSchemaObjectProperties); ok {
+				merged = append(merged, props...)
+			}
+		}
+		return merged
+	}
+
+	switch {
+	case len(schema.Example) > 0:
+		return typedExample(schema.Type, schema.Example)
+	case len(schema.Default) > 0:
+		return typedExample(schema.Type, schema.Default)
+	case len(schema.Enum) > 0:
+		return typedExample(schema.Type, schema.Enum[0])
+	}
+
+	switch schema.Type {
+	case "array":
+		if schema.Items == nil {
+			return []interface{}{}
+		}
+		item := exampleOf(swaggerSchemaObject(*schema.Items), d, seen)
+		if item == nil {
+			return []interface{}{}
+		}
+		return []interface{}{item}
+	case "integer", "number":
+		return 0
+	case "boolean":
+		return false
+	case "string":
+		if schema.Format == "date-time" {
+			return "2006-01-02T15:04:05Z"
+		}
+		return "string"
+	case "file":
+		return ""
+	}
+
+	props := swaggerSchemaObjectProperties{}
+	if schema.Properties != nil {
+		for _, kv := range *schema.Properties {
+			if prop, ok := kv.Value.(swaggerSchemaObject); ok {
+				props = append(props, keyVal{Key: kv.Key, Value: exampleOf(prop, d, seen)})
+			}
+		}
+	}
+	if schema.AdditionalProperties != nil {
+		props = append(props, keyVal{Key: "key", Value: exampleOf(*schema.AdditionalProperties, d, seen)})
+	}
+	return props
+}
+
+// typedExample converts the example, default or enum value v, which the
+// schema keeps as a string, to the json type t.
+func typedExample(t, v string) interface{} {
+	switch t {
+	case "integer":
+		if i, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return i
+		}
+	case "number":
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			return f
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(v); err == nil {
+			return b
+		}
+	case "array", "object":
+		var value interface{}
+		if err := json.Unmarshal([]byte(v), &value); err == nil {
+			return value
+		}
+	}
+	return v
+}
+
+// exampleJSON renders the sample value of schema as indented JSON.
+func exampleJSON(schema swaggerSchemaObject, d swaggerDefinitionsObject) (string, error) {
+	data, err := json.MarshalIndent(exampleOf(schema, d, map[string]bool{}), "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// parameterExample is the sample value of a path, query, header or form
+// parameter, empty if nothing suggests one.
+func parameterExample(param swaggerParameterObject) string {
+	switch {
+	case len(param.Example) > 0:
+		return param.Example
+	case len(param.Default) > 0:
+		return param.Default
+	case len(param.Enum) > 0:
+		return param.Enum[0]
+	default:
+		return ""
+	}
+}
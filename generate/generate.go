@@ -3,30 +3,190 @@ package generate
 import (
 	"bytes"
 	"encoding/json"
-	"fmt"
+	"errors"
 	"io/ioutil"
+	"path/filepath"
+	"strings"
 
 	plugin2 "github.com/zeromicro/go-zero/tools/goctl/plugin"
 )
 
+const (
+	// SpecSwagger2 renders a Swagger 2.0 document, which is the default.
+	SpecSwagger2 = "swagger2"
+	// SpecOpenAPI3 renders an OpenAPI 3.0 document.
+	SpecOpenAPI3 = "openapi3"
+	// SpecOpenAPI31 renders an OpenAPI 3.1 document whose schemas are JSON Schema 2020-12.
+	SpecOpenAPI31 = "openapi3.1"
+)
+
+const (
+	// EmbedFlatten copies the members of embedded structs into the definition, which is the default.
+	EmbedFlatten = "flatten"
+	// EmbedAllOf renders embedded structs as allOf references to their definitions.
+	EmbedAllOf = "allof"
+)
+
+const (
+	// FormatJSON writes the document as JSON.
+	FormatJSON = "json"
+	// FormatYAML writes the document as YAML.
+	FormatYAML = "yaml"
+)
+
+// Options controls what is generated and where it is written.
+type Options struct {
+	Filename string
+	Host     string
+	BasePath string
+	// Spec is one of SpecSwagger2, SpecOpenAPI3 and SpecOpenAPI31, empty means SpecSwagger2.
+	Spec string
+	// Format is FormatJSON or FormatYAML, empty means inferring it from the
+	// extension of Filename and falling back to FormatJSON.
+	Format string
+	// Embed is EmbedFlatten or EmbedAllOf, empty means EmbedFlatten.
+	Embed string
+	// OperationID is OperationIDHandler, OperationIDGroupHandler, OperationIDMethodPath
+	// or a template such as {group}_{handler}, empty means OperationIDHandler.
+	OperationID string
+	// Prune removes the definitions no route refers to and the empty ones.
+	Prune bool
+	// Config is the content of the configuration file, nil means the defaults.
+	Config *Config
+}
+
+// OutputFormat returns the format the document will be written in.
+func (o Options) OutputFormat() string {
+	if len(o.Format) > 0 {
+		return o.Format
+	}
+
+	switch strings.ToLower(filepath.Ext(o.Filename)) {
+	case ".yaml", ".yml":
+		return FormatYAML
+	default:
+		return FormatJSON
+	}
+}
+
 func Do(filename string, host string, basePath string, in *plugin2.Plugin) error {
-	swagger, err := applyGenerate(in, host, basePath)
+	return DoWithOptions(in, Options{
+		Filename: filename,
+		Host:     host,
+		BasePath: basePath,
+	})
+}
+
+func DoWithOptions(in *plugin2.Plugin, opt Options) error {
+	content, err := Render(in, opt)
 	if err != nil {
-		fmt.Println(err)
+		return err
+	}
+
+	return write(in, opt.Filename, content)
+}
+
+// Render generates the document of in without writing it, opt.Filename is
+// only used to infer the format.
+func Render(in *plugin2.Plugin, opt Options) ([]byte, error) {
+	swagger, err := build(in, opt)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc interface{}
+	switch opt.Spec {
+	case "", SpecSwagger2:
+		doc = swagger
+	case SpecOpenAPI3:
+		doc = convertToOpenAPI3(swagger)
+	case SpecOpenAPI31:
+		doc = convertToOpenAPI31(swagger)
+	default:
+		return nil, &OptionError{Option: "spec", Value: opt.Spec, Expected: []string{SpecSwagger2, SpecOpenAPI3, SpecOpenAPI31}}
 	}
+
 	var formatted bytes.Buffer
 	enc := json.NewEncoder(&formatted)
 	enc.SetIndent("", "  ")
 
-	if err := enc.Encode(swagger); err != nil {
-		fmt.Println(err)
+	if err := enc.Encode(doc); err != nil {
+		return nil, &WriteError{Path: opt.Filename, Err: err}
 	}
 
-	output := in.Dir + "/" + filename
+	content := formatted.Bytes()
+	switch format := opt.OutputFormat(); format {
+	case FormatJSON:
+	case FormatYAML:
+		content, err = jsonToYAML(content)
+		if err != nil {
+			return nil, &WriteError{Path: opt.Filename, Err: err}
+		}
+	default:
+		return nil, &OptionError{Option: "format", Value: format, Expected: []string{FormatJSON, FormatYAML}}
+	}
 
-	err = ioutil.WriteFile(output, formatted.Bytes(), 0666)
-	if err != nil {
-		fmt.Println(err)
+	return content, nil
+}
+
+// build checks opt and walks the routes of in into the Swagger 2.0 model all
+// the outputs are rendered from.
+func build(in *plugin2.Plugin, opt Options) (*swaggerObject, error) {
+	if in == nil || in.Api == nil {
+		return nil, &InputError{Err: errors.New("no api spec in plugin payload")}
+	}
+
+	cfg := opt.Config
+	if cfg == nil {
+		cfg = &Config{}
+	}
+
+	switch opt.Embed {
+	case "", EmbedFlatten, EmbedAllOf:
+	default:
+		return nil, &OptionError{Option: "embed", Value: opt.Embed, Expected: []string{EmbedFlatten, EmbedAllOf}}
+	}
+
+	if err := checkOperationIDStrategy(opt.OperationID); err != nil {
+		return nil, err
+	}
+
+	return applyGenerate(in, opt, cfg)
+}
+
+// tagOperations are the operations of a tag, the group or swtags of the routes.
+type tagOperations struct {
+	Tag        string
+	Operations []operationRef
+}
+
+// operationsByTag groups the operations of s by their first tag, the tags
+// and the operations are in route order.
+func operationsByTag(s *swaggerObject) []tagOperations {
+	var ret []tagOperations
+	index := map[string]int{}
+	for _, ref := range s.operations {
+		tag := ""
+		if tags := s.Paths[ref.Path].operation(ref.Method).Tags; len(tags) > 0 {
+			// swtags: "订单" keeps its quotes
+			tag = strings.Trim(tags[0], `"`)
+		}
+		i, ok := index[tag]
+		if !ok {
+			i = len(ret)
+			index[tag] = i
+			ret = append(ret, tagOperations{Tag: tag})
+		}
+		ret[i].Operations = append(ret[i].Operations, ref)
+	}
+	return ret
+}
+
+// write saves content as filename in the output directory of in.
+func write(in *plugin2.Plugin, filename string, content []byte) error {
+	output := in.Dir + "/" + filename
+	if err := ioutil.WriteFile(output, content, 0666); err != nil {
+		return &WriteError{Path: output, Err: err}
 	}
-	return err
+	return nil
 }
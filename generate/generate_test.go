@@ -0,0 +1,38 @@
+package generate
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/zeromicro/go-zero/tools/goctl/api/parser"
+	plugin2 "github.com/zeromicro/go-zero/tools/goctl/plugin"
+)
+
+func TestMain(m *testing.M) {
+	// the warnings of the inputs are expected, tests check them with captureLog
+	SetLogOutput(io.Discard)
+	os.Exit(m.Run())
+}
+
+// loadAPI parses an .api file of the tests directory.
+func loadAPI(t *testing.T, name string) *plugin2.Plugin {
+	t.Helper()
+	file := "../tests/" + name
+	api, err := parser.Parse(file)
+	if err != nil {
+		t.Fatalf("parse %s: %v", file, err)
+	}
+	return &plugin2.Plugin{Api: api, ApiFilePath: file, Dir: t.TempDir()}
+}
+
+// captureLog returns the diagnostics written while fn runs.
+func captureLog(t *testing.T, fn func()) string {
+	t.Helper()
+	var buf bytes.Buffer
+	SetLogOutput(&buf)
+	defer SetLogOutput(io.Discard)
+	fn()
+	return buf.String()
+}
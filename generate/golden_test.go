@@ -0,0 +1,119 @@
+package generate_test
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dyntrait/goctl-swagger/generate"
+	"github.com/dyntrait/goctl-swagger/validate"
+	"github.com/zeromicro/go-zero/tools/goctl/api/parser"
+	plugin2 "github.com/zeromicro/go-zero/tools/goctl/plugin"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files of testdata")
+
+// specCases are the documents rendered from the inputs of the tests directory.
+var specCases = []struct {
+	golden string
+	api    string
+	config string
+	opt    generate.Options
+}{
+	{golden: "user.swagger.json", api: "user.api"},
+	{golden: "shop.openapi3.json", api: "shop.api", config: "shop.yaml", opt: generate.Options{Spec: generate.SpecOpenAPI3}},
+	{golden: "shop.openapi31.json", api: "shop.api", config: "shop.yaml", opt: generate.Options{Spec: generate.SpecOpenAPI31}},
+	{golden: "user.swagger.yaml", api: "user.api", opt: generate.Options{Filename: "user.yaml"}},
+	{golden: "shop.swagger.json", api: "shop.api", config: "shop.yaml", opt: generate.Options{Host: "localhost:8888", BasePath: "/"}},
+	{golden: "respdoc.swagger.json", api: "respdoc.api"},
+	{golden: "middleware.swagger.json", api: "middleware.api", config: "middleware.yaml"},
+	{golden: "embed.swagger.json", api: "embed.api"},
+	{golden: "upload.swagger.json", api: "upload.api"},
+	{golden: "cyclic.swagger.json", api: "cyclic.api"},
+	{golden: "shop.allof.swagger.json", api: "shop.api", opt: generate.Options{Embed: generate.EmbedAllOf, Prune: true, OperationID: generate.OperationIDGroupHandler}},
+	{golden: "operationid.swagger.json", api: "operationid.api"},
+}
+
+// loadAPI parses an .api file of the tests directory.
+func loadAPI(t *testing.T, name string) *plugin2.Plugin {
+	t.Helper()
+	file := filepath.Join("..", "tests", name)
+	api, err := parser.Parse(file)
+	if err != nil {
+		t.Fatalf("parse %s: %v", file, err)
+	}
+	return &plugin2.Plugin{Api: api, ApiFilePath: file, Dir: t.TempDir()}
+}
+
+// loadConfig reads a configuration file of the tests directory, nil if name is empty.
+func loadConfig(t *testing.T, name string) *generate.Config {
+	t.Helper()
+	if len(name) == 0 {
+		return nil
+	}
+	cfg, err := generate.LoadConfig(filepath.Join("..", "tests", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cfg
+}
+
+// checkGolden compares got with the golden file name of testdata, rewriting
+// it first with -update.
+func checkGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	golden := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(golden, got, 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("%v, run go test -update to create it", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("output differs from %s, run go test -update and review the diff", golden)
+	}
+}
+
+func TestGolden(t *testing.T) {
+	for _, tt := range specCases {
+		t.Run(tt.golden, func(t *testing.T) {
+			opt := tt.opt
+			opt.Config = loadConfig(t, tt.config)
+			got, err := generate.Render(loadAPI(t, tt.api), opt)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkGolden(t, tt.golden, got)
+
+			problems, err := validate.Document(got)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, p := range problems {
+				t.Errorf("invalid document: %v", p)
+			}
+		})
+	}
+}
+
+func TestOptionErrors(t *testing.T) {
+	tests := []generate.Options{
+		{Spec: "swagger3"},
+		{Format: "toml"},
+		{Embed: "inline"},
+		{OperationID: "{foo}"},
+	}
+	in := loadAPI(t, "user.api")
+	for _, opt := range tests {
+		var optErr *generate.OptionError
+		if _, err := generate.Render(in, opt); !errors.As(err, &optErr) {
+			t.Errorf("Render(%+v) = %v, want an OptionError", opt, err)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package generate
+
+import (
+	"strconv"
+
+	"github.com/dyntrait/goctl-swagger/ui"
+	plugin2 "github.com/zeromicro/go-zero/tools/goctl/plugin"
+)
+
+// DoHTML writes the offline HTML documentation of in.
+func DoHTML(in *plugin2.Plugin, opt Options) error {
+	content, err := RenderHTML(in, opt)
+	if err != nil {
+		return err
+	}
+	return write(in, opt.Filename, content)
+}
+
+// RenderHTML generates a single HTML page documenting in, with the Swagger
+// 2.0 document and its renderer inlined so that it needs no network.
+func RenderHTML(in *plugin2.Plugin, opt Options) ([]byte, error) {
+	filename := opt.Filename
+	opt.Format = FormatJSON
+	spec, err := Render(in, opt)
+	if err != nil {
+		return nil, err
+	}
+
+	title, _ := strconv.Unquote(in.Api.Info.Properties["title"])
+	if len(title) == 0 {
+		title = in.Api.Service.Name
+	}
+	content, err := ui.Page(title, spec)
+	if err != nil {
+		return nil, &WriteError{Path: filename, Err: err}
+	}
+	return content, nil
+}
@@ -0,0 +1,18 @@
+package generate_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/dyntrait/goctl-swagger/generate"
+)
+
+func TestRenderHTML(t *testing.T) {
+	page, err := generate.RenderHTML(loadAPI(t, "shop.api"), generate.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(page, []byte("<title>商城</title>")) || !bytes.Contains(page, []byte(`"operationId": "listProducts"`)) {
+		t.Error("the page does not inline the document")
+	}
+}
@@ -0,0 +1,195 @@
+package generate
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	plugin2 "github.com/zeromicro/go-zero/tools/goctl/plugin"
+)
+
+const (
+	// httpEnvironment is the environment of the generated environment file.
+	httpEnvironment = "dev"
+	httpBoundary    = "WebAppBoundary"
+)
+
+// httpFileNameReplacer replaces the characters a tag cannot keep in a file name.
+var httpFileNameReplacer = strings.NewReplacer("/", "_", "\\", "_", ":", "_", "*", "_", "?", "_",
+	"\"", "_", "<", "_", ">", "_", "|", "_", " ", "_")
+
+// DoHTTP writes a .http request file per tag, and the environment file
+// opt.Filename, into the output directory.
+func DoHTTP(in *plugin2.Plugin, opt Options) error {
+	files, err := RenderHTTP(in, opt)
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := write(in, name, files[name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RenderHTTP generates the REST client request files of the JetBrains IDEs
+// and VS Code, a <tag>.http file per tag, along with the environment file
+// opt.Filename holding host, basePath, token and the path parameters. The
+// result maps the file names to their content.
+func RenderHTTP(in *plugin2.Plugin, opt Options) (map[string][]byte, error) {
+	s, err := build(in, opt)
+	if err != nil {
+		return nil, err
+	}
+
+	host := s.Host
+	if len(host) == 0 {
+		host = defaultHost
+	}
+	env := swaggerSchemaObjectProperties{
+		{Key: "host", Value: host},
+		{Key: "basePath", Value: s.BasePath},
+		{Key: tokenVariable, Value: ""},
+	}
+	seen := map[string]bool{"host": true, "basePath": true, tokenVariable: true}
+
+	files := map[string][]byte{}
+	for _, tag := range operationsByTag(s) {
+		name := httpFileNameReplacer.Replace(tag.Tag)
+		if len(name) == 0 {
+			name = in.Api.Service.Name
+		}
+		name += ".http"
+
+		var buf bytes.Buffer
+		for i, ref := range tag.Operations {
+			op := s.Paths[ref.Path].operation(ref.Method)
+			if i > 0 {
+				buf.WriteString("\n")
+			}
+			if err := writeHTTPRequest(&buf, s, ref, op); err != nil {
+				return nil, &WriteError{Path: name, Err: err}
+			}
+
+			// path parameters are environment variables, so that the requests run as they are
+			for _, param := range op.Parameters {
+				if param.In == "path" && !seen[param.Name] {
+					seen[param.Name] = true
+					env = append(env, keyVal{Key: param.Name, Value: parameterExample(param)})
+				}
+			}
+		}
+
+		files[name] = buf.Bytes()
+	}
+
+	content, err := json.MarshalIndent(swaggerSchemaObjectProperties{{Key: httpEnvironment, Value: env}}, "", "  ")
+	if err != nil {
+		return nil, &WriteError{Path: opt.Filename, Err: err}
+	}
+	files[opt.Filename] = append(content, '\n')
+	return files, nil
+}
+
+// writeHTTPRequest writes op as a request of the REST client syntax:
+//
+//	### summary
+//	POST http://{{host}}{{basePath}}/api/user/{{id}}?name=
+//	Content-Type: application/json
+//
+//	{"name": "string"}
+func writeHTTPRequest(buf *bytes.Buffer, s *swaggerObject, ref operationRef, op *swaggerOperationObject) error {
+	title := op.Summary
+	if len(title) == 0 {
+		title = op.OperationID
+	}
+	fmt.Fprintf(buf, "### %s\n", title)
+	for _, line := range strings.Split(op.Description, "\n") {
+		if len(strings.TrimSpace(line)) > 0 {
+			fmt.Fprintf(buf, "# %s\n", strings.TrimSpace(line))
+		}
+	}
+
+	var query, headers, form []string
+	body := ""
+	for _, param := range op.Parameters {
+		value := parameterExample(param)
+		switch param.In {
+		case "query":
+			query = append(query, url.QueryEscape(param.Name)+"="+url.QueryEscape(value))
+		case "header":
+			headers = append(headers, param.Name+": "+value)
+		case "formData":
+			form = append(form, url.QueryEscape(param.Name)+"="+url.QueryEscape(value))
+		case "body":
+			if param.Schema == nil {
+				continue
+			}
+			sample, err := exampleJSON(*param.Schema, s.Definitions)
+			if err != nil {
+				return err
+			}
+			headers = append(headers, "Content-Type: application/json")
+			body = sample
+		}
+	}
+	for _, scheme := range securitySchemesOf(s, op) {
+		switch scheme.In {
+		case "header":
+			headers = append(headers, scheme.Name+": {{"+tokenVariable+"}}")
+		case "query":
+			query = append(query, url.QueryEscape(scheme.Name)+"={{"+tokenVariable+"}}")
+		}
+	}
+
+	// multipart is needed for files, the other forms are url encoded
+	if contains(op.Consumes, mimeMultipartForm) {
+		headers = append(headers, "Content-Type: "+mimeMultipartForm+"; boundary="+httpBoundary)
+		body = multipartBody(op.Parameters)
+	} else if len(form) > 0 {
+		headers = append(headers, "Content-Type: "+mimeURLEncodedForm)
+		body = strings.Join(form, "&")
+	}
+
+	target := "http://{{host}}{{basePath}}" + pathTemplatePattern.ReplaceAllString(ref.Path, "{{$1}}")
+	if len(query) > 0 {
+		target += "?" + strings.Join(query, "&")
+	}
+	fmt.Fprintf(buf, "%s %s\n", strings.ToUpper(ref.Method), target)
+	for _, header := range headers {
+		buf.WriteString(header + "\n")
+	}
+	if len(body) > 0 {
+		fmt.Fprintf(buf, "\n%s\n", body)
+	}
+	return nil
+}
+
+// multipartBody renders the form parameters as a multipart body, the file
+// parameters upload the file of the same name next to the .http file.
+func multipartBody(params swaggerParametersObject) string {
+	var b strings.Builder
+	for _, param := range params {
+		if param.In != "formData" {
+			continue
+		}
+		fmt.Fprintf(&b, "--%s\n", httpBoundary)
+		if param.Type == "file" {
+			fmt.Fprintf(&b, "Content-Disposition: form-data; name=%q; filename=%q\n\n< ./%s\n", param.Name, param.Name, param.Name)
+			continue
+		}
+		fmt.Fprintf(&b, "Content-Disposition: form-data; name=%q\n\n%s\n", param.Name, parameterExample(param))
+	}
+	fmt.Fprintf(&b, "--%s--", httpBoundary)
+	return b.String()
+}
@@ -0,0 +1,34 @@
+package generate_test
+
+import (
+	"bytes"
+	"sort"
+	"testing"
+
+	"github.com/dyntrait/goctl-swagger/generate"
+)
+
+func TestHTTPGolden(t *testing.T) {
+	files, err := generate.RenderHTTP(loadAPI(t, "shop.api"), generate.Options{Filename: "http-client.env.json", Config: loadConfig(t, "shop.yaml")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "shop.http", joinFiles(files))
+}
+
+// joinFiles concatenates files in name order, each preceded by its name.
+func joinFiles(files map[string][]byte) []byte {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	for _, name := range names {
+		buf.WriteString("# file: " + name + "\n")
+		buf.Write(files[name])
+		buf.WriteString("\n")
+	}
+	return buf.Bytes()
+}
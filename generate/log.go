@@ -1,16 +1,100 @@
 package generate
 
 import (
-	"gopkg.in/natefinch/lumberjack.v2"
+	"fmt"
+	"io"
 	"log"
+	"os"
+	"strconv"
+	"strings"
 )
 
-func init() {
-	log.SetOutput(&lumberjack.Logger{
-		Filename:   "./foo.log",
-		MaxSize:    500, // megabytes
-		MaxBackups: 3,
-		MaxAge:     28,   //days
-		Compress:   true, // disabled by default
-	})
+// Log levels accepted by SetLogLevel.
+const (
+	LogLevelDebug = "debug"
+	LogLevelInfo  = "info"
+	LogLevelWarn  = "warn"
+	LogLevelError = "error"
+)
+
+var logLevels = []string{LogLevelDebug, LogLevelInfo, LogLevelWarn, LogLevelError}
+
+var (
+	logger   = log.New(os.Stderr, "", 0)
+	logLevel = 2 // index of LogLevelWarn in logLevels
+)
+
+// SetLogOutput redirects the diagnostics of the generator, stderr by default.
+func SetLogOutput(w io.Writer) {
+	logger.SetOutput(w)
+}
+
+// SetLogLevel sets the lowest level written by the generator, warn by default.
+func SetLogLevel(level string) error {
+	for i, l := range logLevels {
+		if l == strings.ToLower(level) {
+			logLevel = i
+			return nil
+		}
+	}
+
+	return &OptionError{Option: "log level", Value: level, Expected: logLevels}
+}
+
+// warning describes a construct the generator skipped or could only approximate.
+type warning struct {
+	Route  string
+	Type   string
+	Member string
+	Reason string
+}
+
+func warn(w warning) {
+	logKV(LogLevelWarn, "route", w.Route, "type", w.Type, "member", w.Member, "reason", w.Reason)
+}
+
+func debugf(format string, args ...interface{}) {
+	logKV(LogLevelDebug, "msg", fmt.Sprintf(format, args...))
+}
+
+func infof(format string, args ...interface{}) {
+	logKV(LogLevelInfo, "msg", fmt.Sprintf(format, args...))
+}
+
+// logKV writes one logfmt line such as: level=warn type=Foo member=Bar reason="..."
+// Empty values are left out.
+func logKV(level string, kvs ...string) {
+	if !logEnabled(level) {
+		return
+	}
+
+	var b strings.Builder
+	b.WriteString("level=")
+	b.WriteString(level)
+	for i := 0; i+1 < len(kvs); i += 2 {
+		if len(kvs[i+1]) == 0 {
+			continue
+		}
+		b.WriteString(" ")
+		b.WriteString(kvs[i])
+		b.WriteString("=")
+		b.WriteString(logValue(kvs[i+1]))
+	}
+	logger.Println(b.String())
+}
+
+func logEnabled(level string) bool {
+	for i, l := range logLevels {
+		if l == level {
+			return i >= logLevel
+		}
+	}
+	return false
+}
+
+func logValue(v string) string {
+	if strings.ContainsAny(v, " \t\"=") {
+		return strconv.Quote(v)
+	}
+	return v
 }
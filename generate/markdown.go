@@ -0,0 +1,298 @@
+package generate
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	plugin2 "github.com/zeromicro/go-zero/tools/goctl/plugin"
+)
+
+var (
+	markdownParameterHeader = []string{"Name", "In", "Type", "Required", "Enum", "Range", "Default", "Example", "Description"}
+	markdownFieldHeader     = []string{"Field", "Type", "Required", "Enum", "Range", "Default", "Example", "Description"}
+	markdownCellReplacer    = strings.NewReplacer("|", "\\|", "\r\n", "<br>", "\n", "<br>")
+)
+
+// markdownField is a row of a field table, Name is the path of the field
+// such as items[].name.
+type markdownField struct {
+	Name     string
+	Schema   swaggerSchemaObject
+	Required bool
+}
+
+// DoMarkdown writes the Markdown reference of in.
+func DoMarkdown(in *plugin2.Plugin, opt Options) error {
+	content, err := RenderMarkdown(in, opt)
+	if err != nil {
+		return err
+	}
+	return write(in, opt.Filename, content)
+}
+
+// RenderMarkdown generates a Markdown reference with a section per tag and
+// an operation per route, listing its parameters and the fields of its
+// request and response bodies with the nested types expanded.
+func RenderMarkdown(in *plugin2.Plugin, opt Options) ([]byte, error) {
+	s, err := build(in, opt)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	title := s.Info.Title
+	if len(title) == 0 {
+		title = in.Api.Service.Name
+	}
+	fmt.Fprintf(&buf, "# %s\n", title)
+	if len(s.Info.Version) > 0 {
+		fmt.Fprintf(&buf, "\nVersion: %s\n", s.Info.Version)
+	}
+	if len(s.Info.Description) > 0 {
+		fmt.Fprintf(&buf, "\n%s\n", s.Info.Description)
+	}
+	if len(s.Host) > 0 || len(s.BasePath) > 0 {
+		fmt.Fprintf(&buf, "\nBase URL: `%s%s`\n", s.Host, s.BasePath)
+	}
+
+	for _, tag := range operationsByTag(s) {
+		fmt.Fprintf(&buf, "\n## %s\n", tag.Tag)
+		for _, ref := range tag.Operations {
+			writeMarkdownOperation(&buf, s, ref, s.Paths[ref.Path].operation(ref.Method))
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+func writeMarkdownOperation(buf *bytes.Buffer, s *swaggerObject, ref operationRef, op *swaggerOperationObject) {
+	title := op.Summary
+	if len(title) == 0 {
+		title = op.OperationID
+	}
+	fmt.Fprintf(buf, "\n### %s\n\n`%s %s`\n", title, strings.ToUpper(ref.Method), ref.Path)
+	if len(op.Description) > 0 {
+		fmt.Fprintf(buf, "\n%s\n", op.Description)
+	}
+	if op.Deprecated {
+		buf.WriteString("\n**Deprecated**\n")
+	}
+	if op.Security != nil && len(*op.Security) > 0 {
+		fmt.Fprintf(buf, "\nAuthorization: `%s`\n", strings.Join(sortedSchemeNames((*op.Security)[0]), "`, `"))
+	}
+
+	var rows [][]string
+	var body *swaggerParameterObject
+	for i, param := range op.Parameters {
+		if param.In == "body" {
+			body = &op.Parameters[i]
+			continue
+		}
+		rows = append(rows, []string{
+			"`" + param.Name + "`",
+			param.In,
+			parameterTypeName(param),
+			yesNo(param.Required),
+			strings.Join(param.Enum, ", "),
+			rangeOf(param.Minimum, param.Maximum, param.ExclusiveMinimum, param.ExclusiveMaximum),
+			param.Default,
+			param.Example,
+			param.Description,
+		})
+	}
+	if len(rows) > 0 {
+		buf.WriteString("\n**Parameters**\n\n")
+		writeMarkdownTable(buf, markdownParameterHeader, rows)
+	}
+
+	if body != nil && body.Schema != nil {
+		fmt.Fprintf(buf, "\n**Request body** %s\n", codeSpan(schemaTypeName(*body.Schema)))
+		if len(body.Description) > 0 {
+			fmt.Fprintf(buf, "\n%s\n", body.Description)
+		}
+		writeMarkdownFields(buf, s, *body.Schema)
+	}
+
+	codes := make([]string, 0, len(op.Responses))
+	for code := range op.Responses {
+		codes = append(codes, code)
+	}
+	sortResponseCodes(codes)
+
+	rows = nil
+	for _, code := range codes {
+		resp := op.Responses[code]
+		typeName := ""
+		if resp.Schema != nil {
+			typeName = codeSpan(schemaTypeName(*resp.Schema))
+		}
+		rows = append(rows, []string{code, typeName, resp.Description})
+	}
+	buf.WriteString("\n**Responses**\n\n")
+	writeMarkdownTable(buf, []string{"Code", "Type", "Description"}, rows)
+
+	for _, code := range codes {
+		resp := op.Responses[code]
+		if resp.Schema == nil || len(markdownFields(s, *resp.Schema, "", map[string]bool{})) == 0 {
+			continue
+		}
+		fmt.Fprintf(buf, "\n**Response %s** %s\n", code, codeSpan(schemaTypeName(*resp.Schema)))
+		writeMarkdownFields(buf, s, *resp.Schema)
+	}
+}
+
+func writeMarkdownFields(buf *bytes.Buffer, s *swaggerObject, schema swaggerSchemaObject) {
+	fields := markdownFields(s, schema, "", map[string]bool{})
+	if len(fields) == 0 {
+		return
+	}
+
+	var rows [][]string
+	for _, f := range fields {
+		rows = append(rows, []string{
+			"`" + f.Name + "`",
+			schemaTypeName(f.Schema),
+			yesNo(f.Required),
+			strings.Join(f.Schema.Enum, ", "),
+			rangeOf(f.Schema.Minimum, f.Schema.Maximum, f.Schema.ExclusiveMinimum, f.Schema.ExclusiveMaximum),
+			f.Schema.Default,
+			f.Schema.Example,
+			f.Schema.Description,
+		})
+	}
+	buf.WriteString("\n")
+	writeMarkdownTable(buf, markdownFieldHeader, rows)
+}
+
+// markdownFields lists the fields of schema and of the types nested in it,
+// prefix is the path of schema. A type is not expanded inside itself.
+func markdownFields(s *swaggerObject, schema swaggerSchemaObject, prefix string, seen map[string]bool) []markdownField {
+	if len(schema.Ref) > 0 {
+		name := strings.TrimPrefix(schema.Ref, swaggerDefinitionRef)
+		def, ok := s.Definitions[name]
+		if !ok || seen[name] {
+			return nil
+		}
+		seen[name] = true
+		defer delete(seen, name)
+		return markdownFields(s, def, prefix, seen)
+	}
+
+	var ret []markdownField
+	for _, part := range schema.AllOf {
+		ret = append(ret, markdownFields(s, part, prefix, seen)...)
+	}
+	switch {
+	case schema.Type == "array" && schema.Items != nil:
+		return append(ret, markdownFields(s, swaggerSchemaObject(*schema.Items), prefix+"[]", seen)...)
+	case schema.AdditionalProperties != nil:
+		return append(ret, markdownFields(s, *schema.AdditionalProperties, prefix+"{}", seen)...)
+	case schema.Properties == nil:
+		return ret
+	}
+
+	if len(prefix) > 0 {
+		prefix += "."
+	}
+	for _, kv := range *schema.Properties {
+		prop, ok := kv.Value.(swaggerSchemaObject)
+		if !ok {
+			continue
+		}
+		ret = append(ret, markdownField{Name: prefix + kv.Key, Schema: prop, Required: contains(schema.Required, kv.Key)})
+		ret = append(ret, markdownFields(s, prop, prefix+kv.Key, seen)...)
+	}
+	return ret
+}
+
+// schemaTypeName names the type of schema in Go syntax, such as
+// []UserInfo or map[string]integer.
+func schemaTypeName(schema swaggerSchemaObject) string {
+	switch {
+	case len(schema.Ref) > 0:
+		return strings.TrimPrefix(schema.Ref, swaggerDefinitionRef)
+	case schema.Type == "array" && schema.Items != nil:
+		return "[]" + schemaTypeName(swaggerSchemaObject(*schema.Items))
+	case schema.AdditionalProperties != nil:
+		return "map[string]" + schemaTypeName(*schema.AdditionalProperties)
+	case len(schema.Format) > 0:
+		return schema.Type + "(" + schema.Format + ")"
+	case len(schema.Type) > 0:
+		return schema.Type
+	default:
+		return "object"
+	}
+}
+
+func parameterTypeName(param swaggerParameterObject) string {
+	return schemaTypeName(swaggerSchemaObject{schemaCore: schemaCore{Type: param.Type, Format: param.Format, Items: param.Items}})
+}
+
+// rangeOf renders the bounds set by the range option.
+func rangeOf(min, max *float64, exclusiveMin, exclusiveMax bool) string {
+	format := func(f float64) string {
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	}
+
+	switch {
+	case min != nil && max != nil:
+		open, end := "[", "]"
+		if exclusiveMin {
+			open = "("
+		}
+		if exclusiveMax {
+			end = ")"
+		}
+		return open + format(*min) + ", " + format(*max) + end
+	case min != nil:
+		if exclusiveMin {
+			return "> " + format(*min)
+		}
+		return ">= " + format(*min)
+	case max != nil:
+		if exclusiveMax {
+			return "< " + format(*max)
+		}
+		return "<= " + format(*max)
+	default:
+		return ""
+	}
+}
+
+func writeMarkdownTable(buf *bytes.Buffer, header []string, rows [][]string) {
+	buf.WriteString("| " + strings.Join(header, " | ") + " |\n")
+	buf.WriteString("|" + strings.Repeat(" --- |", len(header)) + "\n")
+	for _, row := range rows {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			cells[i] = markdownCellReplacer.Replace(strings.TrimSpace(cell))
+		}
+		buf.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+	}
+}
+
+// sortResponseCodes sorts the status codes numerically, default goes last.
+func sortResponseCodes(codes []string) {
+	key := func(code string) int {
+		if n, err := strconv.Atoi(code); err == nil {
+			return n
+		}
+		return 1000
+	}
+	sort.SliceStable(codes, func(i, j int) bool {
+		return key(codes[i]) < key(codes[j])
+	})
+}
+
+func codeSpan(s string) string {
+	return "`" + s + "`"
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
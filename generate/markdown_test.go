@@ -0,0 +1,15 @@
+package generate_test
+
+import (
+	"testing"
+
+	"github.com/dyntrait/goctl-swagger/generate"
+)
+
+func TestMarkdownGolden(t *testing.T) {
+	got, err := generate.RenderMarkdown(loadAPI(t, "shop.api"), generate.Options{Config: loadConfig(t, "shop.yaml")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "shop.md", got)
+}
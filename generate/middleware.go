@@ -0,0 +1,147 @@
+package generate
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/zeromicro/go-zero/tools/goctl/api/spec"
+)
+
+const (
+	middlewareAnnotation = "middleware"
+	middlewareExtension  = "x-middleware"
+	extensionPrefix      = "x-"
+)
+
+// MiddlewareConfig describes what a go-zero middleware means for the
+// operations of the groups declared with @server(middleware: ...).
+type MiddlewareConfig struct {
+	// Security is required in addition to the jwt security of the group.
+	Security []SecurityRequirement `json:"security,omitempty"`
+	// Headers are added as header parameters, e.g. a token checked by the middleware.
+	Headers []MiddlewareHeader `json:"headers,omitempty"`
+	// Extensions are vendor extensions set on the operations, keys start with x-.
+	Extensions map[string]interface{} `json:"extensions,omitempty"`
+}
+
+// MiddlewareHeader is a request header a middleware expects.
+type MiddlewareHeader struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+}
+
+// validateMiddlewares checks the middleware mapping against the declared schemes.
+func (c *Config) validateMiddlewares() error {
+	schemes := c.securitySchemes()
+	for name, m := range c.Middlewares {
+		for _, req := range m.Security {
+			if _, ok := schemes[req.Scheme]; !ok {
+				return fmt.Errorf("middleware %s: undeclared security scheme %q", name, req.Scheme)
+			}
+		}
+		for key := range m.Extensions {
+			if !strings.HasPrefix(key, extensionPrefix) {
+				return fmt.Errorf("middleware %s: extension %q must start with %s", name, key, extensionPrefix)
+			}
+		}
+	}
+
+	return nil
+}
+
+// middlewaresOfGroup splits @server(middleware: AdminCheck, RateLimit).
+func middlewaresOfGroup(group spec.Group) []string {
+	var ret []string
+	for _, name := range strings.Split(strings.Trim(group.GetAnnotation(middlewareAnnotation), `"`), ",") {
+		if name = strings.TrimSpace(name); len(name) > 0 {
+			ret = append(ret, name)
+		}
+	}
+	return ret
+}
+
+// renderMiddlewares applies the middleware mapping of the configuration to op.
+func renderMiddlewares(op *swaggerOperationObject, cfg *Config, group spec.Group) {
+	middlewares := middlewaresOfGroup(group)
+	if len(middlewares) == 0 {
+		return
+	}
+
+	op.addExtension(middlewareExtension, middlewares)
+	for _, name := range middlewares {
+		m, ok := cfg.Middlewares[name]
+		if !ok {
+			debugf("middleware %s is not mapped", name)
+			continue
+		}
+
+		if len(m.Security) > 0 {
+			op.Security = requireAll(op.Security, m.Security)
+		}
+
+		for _, header := range m.Headers {
+			if hasParameter(op.Parameters, "header", header.Name) {
+				continue
+			}
+			op.Parameters = append(op.Parameters, swaggerParameterObject{
+				Name:        header.Name,
+				Description: header.Description,
+				In:          "header",
+				Required:    header.Required,
+				Type:        "string",
+			})
+		}
+
+		for key, value := range m.Extensions {
+			op.addExtension(key, value)
+		}
+	}
+}
+
+// requireAll adds the requirements of a middleware to the existing
+// alternatives, a request has to satisfy both.
+func requireAll(security *[]swaggerSecurityRequirementObject, requirements []SecurityRequirement) *[]swaggerSecurityRequirementObject {
+	var alternatives []swaggerSecurityRequirementObject
+	if security == nil || len(*security) == 0 {
+		alternatives = []swaggerSecurityRequirementObject{{}}
+	} else {
+		alternatives = *security
+	}
+
+	var ret []swaggerSecurityRequirementObject
+	for _, alternative := range alternatives {
+		for _, req := range requirements {
+			combined := swaggerSecurityRequirementObject{}
+			for scheme, scopes := range alternative {
+				combined[scheme] = scopes
+			}
+			combined[req.Scheme] = mergeScopes(combined[req.Scheme], req.Scopes)
+			ret = append(ret, combined)
+		}
+	}
+	return &ret
+}
+
+// mergeScopes returns the sorted union of the scopes a and b, a scheme
+// required by both the jwt group and a middleware needs the scopes of both.
+func mergeScopes(a, b []string) []string {
+	ret := []string{}
+	for _, scope := range append(append([]string{}, a...), b...) {
+		if !contains(ret, scope) {
+			ret = append(ret, scope)
+		}
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+func hasParameter(params swaggerParametersObject, in, name string) bool {
+	for _, p := range params {
+		if p.In == in && strings.EqualFold(p.Name, name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,44 @@
+package generate
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMiddlewareSecurityMergesScopes(t *testing.T) {
+	cfg, err := LoadConfig("../tests/middleware.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := build(loadAPI(t, "middleware.api"), Options{Config: cfg})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []swaggerSecurityRequirementObject{
+		{"userJwt": {}, "oauth": {"admin", "read"}},
+		{"oauth": {"admin", "read", "write"}},
+	}
+	got := s.Paths["/admin/report"].Get.Security
+	if got == nil || !reflect.DeepEqual(*got, want) {
+		t.Errorf("security = %v, want %v", got, want)
+	}
+}
+
+func TestMergeScopes(t *testing.T) {
+	tests := []struct {
+		a, b []string
+		want []string
+	}{
+		{nil, nil, []string{}},
+		{[]string{"read"}, nil, []string{"read"}},
+		{nil, []string{"write", "read"}, []string{"read", "write"}},
+		{[]string{"read"}, []string{"admin"}, []string{"admin", "read"}},
+		{[]string{"write", "read"}, []string{"read", "admin"}, []string{"admin", "read", "write"}},
+	}
+	for _, tt := range tests {
+		if got := mergeScopes(tt.a, tt.b); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("mergeScopes(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
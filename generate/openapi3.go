@@ -0,0 +1,473 @@
+package generate
+
+import (
+	"strings"
+)
+
+const (
+	openapi3Version      = "3.0.3"
+	openapi31Version     = "3.1.0"
+	openapi31Dialect     = "https://spec.openapis.org/oas/3.1/dialect/base"
+	swaggerDefinitionRef = "#/definitions/"
+	openapiComponentRef  = "#/components/schemas/"
+)
+
+// https://spec.openapis.org/oas/v3.0.3#openapi-object
+type openapiObject struct {
+	OpenAPI           string                              `json:"openapi"`
+	JSONSchemaDialect string                              `json:"jsonSchemaDialect,omitempty"`
+	Info              swaggerInfoObject                   `json:"info"`
+	Servers           []openapiServerObject               `json:"servers,omitempty"`
+	Paths             openapiPathsObject                  `json:"paths"`
+	Components        openapiComponentsObject             `json:"components"`
+	Security          []swaggerSecurityRequirementObject  `json:"security,omitempty"`
+	ExternalDocs      *swaggerExternalDocumentationObject `json:"externalDocs,omitempty"`
+}
+
+// https://spec.openapis.org/oas/v3.0.3#server-object
+type openapiServerObject struct {
+	URL         string `json:"url"`
+	Description string `json:"description,omitempty"`
+}
+
+// https://spec.openapis.org/oas/v3.0.3#components-object
+type openapiComponentsObject struct {
+	Schemas         map[string]*openapiSchemaObject        `json:"schemas,omitempty"`
+	SecuritySchemes map[string]openapiSecuritySchemeObject `json:"securitySchemes,omitempty"`
+}
+
+// https://spec.openapis.org/oas/v3.0.3#security-scheme-object
+type openapiSecuritySchemeObject struct {
+	Type         string                   `json:"type"`
+	Description  string                   `json:"description,omitempty"`
+	Name         string                   `json:"name,omitempty"`
+	In           string                   `json:"in,omitempty"`
+	Scheme       string                   `json:"scheme,omitempty"`
+	BearerFormat string                   `json:"bearerFormat,omitempty"`
+	Flows        *openapiOAuthFlowsObject `json:"flows,omitempty"`
+}
+
+// https://spec.openapis.org/oas/v3.0.3#oauth-flows-object
+type openapiOAuthFlowsObject struct {
+	Implicit          *openapiOAuthFlowObject `json:"implicit,omitempty"`
+	Password          *openapiOAuthFlowObject `json:"password,omitempty"`
+	ClientCredentials *openapiOAuthFlowObject `json:"clientCredentials,omitempty"`
+	AuthorizationCode *openapiOAuthFlowObject `json:"authorizationCode,omitempty"`
+}
+
+// https://spec.openapis.org/oas/v3.0.3#oauth-flow-object
+type openapiOAuthFlowObject struct {
+	AuthorizationURL string              `json:"authorizationUrl,omitempty"`
+	TokenURL         string              `json:"tokenUrl,omitempty"`
+	Scopes           swaggerScopesObject `json:"scopes"`
+}
+
+// https://spec.openapis.org/oas/v3.0.3#paths-object
+type openapiPathsObject map[string]openapiPathItemObject
+
+// https://spec.openapis.org/oas/v3.0.3#path-item-object
+type openapiPathItemObject struct {
+	Get    *openapiOperationObject `json:"get,omitempty"`
+	Delete *openapiOperationObject `json:"delete,omitempty"`
+	Post   *openapiOperationObject `json:"post,omitempty"`
+	Put    *openapiOperationObject `json:"put,omitempty"`
+	Patch  *openapiOperationObject `json:"patch,omitempty"`
+}
+
+// https://spec.openapis.org/oas/v3.0.3#operation-object
+type openapiOperationObject struct {
+	Summary     string                    `json:"summary,omitempty"`
+	Description string                    `json:"description,omitempty"`
+	OperationID string                    `json:"operationId"`
+	Parameters  []openapiParameterObject  `json:"parameters,omitempty"`
+	RequestBody *openapiRequestBodyObject `json:"requestBody,omitempty"`
+	Responses   openapiResponsesObject    `json:"responses"`
+	Tags        []string                  `json:"tags,omitempty"`
+	Deprecated  bool                      `json:"deprecated,omitempty"`
+
+	Security     *[]swaggerSecurityRequirementObject `json:"security,omitempty"`
+	ExternalDocs *swaggerExternalDocumentationObject `json:"externalDocs,omitempty"`
+
+	Extensions map[string]interface{} `json:"-"`
+}
+
+// MarshalJSON appends the vendor extensions to the operation.
+func (o openapiOperationObject) MarshalJSON() ([]byte, error) {
+	type operation openapiOperationObject
+	return marshalWithExtensions(operation(o), o.Extensions)
+}
+
+// https://spec.openapis.org/oas/v3.0.3#parameter-object
+type openapiParameterObject struct {
+	Name        string               `json:"name"`
+	In          string               `json:"in"`
+	Description string               `json:"description,omitempty"`
+	Required    bool                 `json:"required"`
+	Schema      *openapiSchemaObject `json:"schema,omitempty"`
+	Example     string               `json:"example,omitempty"`
+}
+
+// https://spec.openapis.org/oas/v3.0.3#request-body-object
+type openapiRequestBodyObject struct {
+	Description string               `json:"description,omitempty"`
+	Content     openapiContentObject `json:"content"`
+	Required    bool                 `json:"required,omitempty"`
+}
+
+// content is keyed by media type, e.g. application/json.
+type openapiContentObject map[string]openapiMediaTypeObject
+
+// https://spec.openapis.org/oas/v3.0.3#media-type-object
+type openapiMediaTypeObject struct {
+	Schema *openapiSchemaObject `json:"schema,omitempty"`
+}
+
+// https://spec.openapis.org/oas/v3.0.3#responses-object
+type openapiResponsesObject map[string]openapiResponseObject
+
+// https://spec.openapis.org/oas/v3.0.3#response-object
+type openapiResponseObject struct {
+	Description string               `json:"description"`
+	Content     openapiContentObject `json:"content,omitempty"`
+}
+
+// https://spec.openapis.org/oas/v3.0.3#schema-object
+// https://spec.openapis.org/oas/v3.1.0#schema-object
+type openapiSchemaObject struct {
+	Ref string `json:"$ref,omitempty"`
+	// Type is a string in 3.0 and may be an array of strings in 3.1.
+	Type     interface{} `json:"type,omitempty"`
+	Format   string      `json:"format,omitempty"`
+	Title    string      `json:"title,omitempty"`
+	Nullable bool        `json:"nullable,omitempty"`
+	Example  string      `json:"example,omitempty"`
+	Examples []string    `json:"examples,omitempty"`
+
+	AllOf []*openapiSchemaObject `json:"allOf,omitempty"`
+	AnyOf []*openapiSchemaObject `json:"anyOf,omitempty"`
+
+	Description string `json:"description,omitempty"`
+
+	Items                *openapiSchemaObject           `json:"items,omitempty"`
+	Properties           *swaggerSchemaObjectProperties `json:"properties,omitempty"`
+	AdditionalProperties *openapiSchemaObject           `json:"additionalProperties,omitempty"`
+
+	Enum    []string `json:"enum,omitempty"`
+	Default string   `json:"default,omitempty"`
+
+	ReadOnly   bool     `json:"readOnly,omitempty"`
+	MultipleOf float64  `json:"multipleOf,omitempty"`
+	Maximum    *float64 `json:"maximum,omitempty"`
+	// ExclusiveMaximum and ExclusiveMinimum are booleans in 3.0 and numbers in 3.1.
+	ExclusiveMaximum interface{} `json:"exclusiveMaximum,omitempty"`
+	Minimum          *float64    `json:"minimum,omitempty"`
+	ExclusiveMinimum interface{} `json:"exclusiveMinimum,omitempty"`
+	MaxLength        uint64      `json:"maxLength,omitempty"`
+	MinLength        uint64      `json:"minLength,omitempty"`
+	Pattern          string      `json:"pattern,omitempty"`
+	MaxItems         uint64      `json:"maxItems,omitempty"`
+	MinItems         uint64      `json:"minItems,omitempty"`
+	UniqueItems      bool        `json:"uniqueItems,omitempty"`
+	MaxProperties    uint64      `json:"maxProperties,omitempty"`
+	MinProperties    uint64      `json:"minProperties,omitempty"`
+	Required         []string    `json:"required,omitempty"`
+}
+
+// convertToOpenAPI3 turns the swagger 2.0 document built by applyGenerate into
+// an OpenAPI 3.0 document, so both outputs share the same route and type analysis.
+func convertToOpenAPI3(s *swaggerObject) *openapiObject {
+	o := &openapiObject{
+		OpenAPI:      openapi3Version,
+		Info:         s.Info,
+		Servers:      openapiServers(s),
+		Paths:        make(openapiPathsObject, len(s.Paths)),
+		Security:     s.Security,
+		ExternalDocs: s.ExternalDocs,
+	}
+
+	if len(s.Definitions) > 0 {
+		o.Components.Schemas = make(map[string]*openapiSchemaObject, len(s.Definitions))
+		for name, schema := range s.Definitions {
+			o.Components.Schemas[name] = openapiSchema(schema)
+		}
+	}
+
+	if len(s.SecurityDefinitions) > 0 {
+		o.Components.SecuritySchemes = make(map[string]openapiSecuritySchemeObject, len(s.SecurityDefinitions))
+		for name, scheme := range s.SecurityDefinitions {
+			o.Components.SecuritySchemes[name] = openapiSecurityScheme(scheme)
+		}
+	}
+
+	for path, item := range s.Paths {
+		o.Paths[path] = openapiPathItemObject{
+			Get:    openapiOperation(item.Get, s.Consumes, s.Produces),
+			Delete: openapiOperation(item.Delete, s.Consumes, s.Produces),
+			Post:   openapiOperation(item.Post, s.Consumes, s.Produces),
+			Put:    openapiOperation(item.Put, s.Consumes, s.Produces),
+			Patch:  openapiOperation(item.Patch, s.Consumes, s.Produces),
+		}
+	}
+
+	return o
+}
+
+// openapiServers folds schemes, host and basePath into the 3.0 servers list.
+func openapiServers(s *swaggerObject) []openapiServerObject {
+	if len(s.Host) == 0 {
+		if len(s.BasePath) == 0 {
+			return nil
+		}
+		return []openapiServerObject{{URL: s.BasePath}}
+	}
+
+	var servers []openapiServerObject
+	for _, scheme := range s.Schemes {
+		servers = append(servers, openapiServerObject{URL: scheme + "://" + s.Host + s.BasePath})
+	}
+	return servers
+}
+
+func openapiSecurityScheme(scheme swaggerSecuritySchemeObject) openapiSecuritySchemeObject {
+	ret := openapiSecuritySchemeObject{
+		Type:        scheme.Type,
+		Description: scheme.Description,
+	}
+
+	switch scheme.Type {
+	case "apiKey":
+		ret.Name = scheme.Name
+		ret.In = scheme.In
+	case "basic":
+		ret.Type = "http"
+		ret.Scheme = "basic"
+	case "oauth2":
+		flow := &openapiOAuthFlowObject{
+			AuthorizationURL: scheme.AuthorizationURL,
+			TokenURL:         scheme.TokenURL,
+			Scopes:           scheme.Scopes,
+		}
+		if flow.Scopes == nil {
+			flow.Scopes = swaggerScopesObject{}
+		}
+
+		ret.Flows = &openapiOAuthFlowsObject{}
+		switch scheme.Flow {
+		case "implicit":
+			ret.Flows.Implicit = flow
+		case "password":
+			ret.Flows.Password = flow
+		case "application":
+			ret.Flows.ClientCredentials = flow
+		case "accessCode":
+			ret.Flows.AuthorizationCode = flow
+		}
+	}
+
+	return ret
+}
+
+func openapiOperation(op *swaggerOperationObject, consumes, produces []string) *openapiOperationObject {
+	if op == nil {
+		return nil
+	}
+
+	ret := &openapiOperationObject{
+		Summary:      op.Summary,
+		Description:  op.Description,
+		OperationID:  op.OperationID,
+		Responses:    make(openapiResponsesObject, len(op.Responses)),
+		Tags:         op.Tags,
+		Deprecated:   op.Deprecated,
+		Security:     op.Security,
+		ExternalDocs: op.ExternalDocs,
+		Extensions:   op.Extensions,
+	}
+	if len(op.Consumes) > 0 {
+		consumes = op.Consumes
+	}
+
+	// 2.0 的 formData 参数在 3.0 中合并为 requestBody 的一个对象
+	var form *openapiSchemaObject
+	for _, param := range op.Parameters {
+		if param.In == "formData" {
+			if form == nil {
+				form = &openapiSchemaObject{Type: "object", Properties: &swaggerSchemaObjectProperties{}}
+			}
+			*form.Properties = append(*form.Properties, keyVal{Key: param.Name, Value: openapiFormProperty(param)})
+			if param.Required {
+				form.Required = append(form.Required, param.Name)
+			}
+			continue
+		}
+
+		if param.In == "body" {
+			// 2.0 的 body 参数在 3.0 中变成 requestBody
+			body := &openapiRequestBodyObject{
+				Description: param.Description,
+				Required:    param.Required,
+				Content:     openapiContentObject{},
+			}
+			for _, mediaType := range consumes {
+				body.Content[mediaType] = openapiMediaTypeObject{Schema: openapiSchema(*param.Schema)}
+			}
+			ret.RequestBody = body
+			continue
+		}
+
+		ret.Parameters = append(ret.Parameters, openapiParameter(param))
+	}
+	if form != nil {
+		body := &openapiRequestBodyObject{Required: len(form.Required) > 0, Content: openapiContentObject{}}
+		for _, mediaType := range consumes {
+			body.Content[mediaType] = openapiMediaTypeObject{Schema: form}
+		}
+		ret.RequestBody = body
+	}
+
+	for code, resp := range op.Responses {
+		r := openapiResponseObject{Description: resp.Description}
+		if resp.Schema != nil && !isEmptySchema(*resp.Schema) {
+			r.Content = openapiContentObject{}
+			for _, mediaType := range produces {
+				r.Content[mediaType] = openapiMediaTypeObject{Schema: openapiSchema(*resp.Schema)}
+			}
+		}
+		ret.Responses[code] = r
+	}
+
+	return ret
+}
+
+func openapiParameter(param swaggerParameterObject) openapiParameterObject {
+	schema := &openapiSchemaObject{
+		Type:    schemaType(param.Type),
+		Format:  param.Format,
+		Enum:    param.Enum,
+		Default: param.Default,
+	}
+	if param.Items != nil {
+		schema.Items = openapiSchema(swaggerSchemaObject(*param.Items))
+	}
+	schema.Minimum = param.Minimum
+	schema.Maximum = param.Maximum
+	if param.ExclusiveMaximum {
+		schema.ExclusiveMaximum = true
+	}
+	if param.ExclusiveMinimum {
+		schema.ExclusiveMinimum = true
+	}
+
+	return openapiParameterObject{
+		Name:        param.Name,
+		In:          param.In,
+		Description: param.Description,
+		Required:    param.Required,
+		Schema:      schema,
+		Example:     param.Example,
+	}
+}
+
+// openapiFormProperty is the schema of a formData parameter in the form
+// object, files are binary strings.
+func openapiFormProperty(param swaggerParameterObject) *openapiSchemaObject {
+	schema := openapiParameter(param).Schema
+	schema.Description = param.Description
+	if param.Type == "file" {
+		schema.Type = "string"
+		schema.Format = "binary"
+	}
+	return schema
+}
+
+func openapiSchemaOfCore(core schemaCore) *openapiSchemaObject {
+	ret := &openapiSchemaObject{
+		Ref:     openapiRef(core.Ref),
+		Type:    schemaType(core.Type),
+		Format:  core.Format,
+		Example: core.Example,
+		Enum:    core.Enum,
+		Default: core.Default,
+	}
+	if core.Items != nil {
+		ret.Items = openapiSchema(swaggerSchemaObject(*core.Items))
+	}
+	return ret
+}
+
+func openapiSchema(s swaggerSchemaObject) *openapiSchemaObject {
+	ret := openapiSchemaOfCore(s.schemaCore)
+	ret.Title = s.Title
+	ret.Description = s.Description
+	ret.ReadOnly = s.ReadOnly
+	ret.MultipleOf = s.MultipleOf
+	ret.Maximum = s.Maximum
+	ret.Minimum = s.Minimum
+	if s.ExclusiveMaximum {
+		ret.ExclusiveMaximum = true
+	}
+	if s.ExclusiveMinimum {
+		ret.ExclusiveMinimum = true
+	}
+	ret.MaxLength = s.MaxLength
+	ret.MinLength = s.MinLength
+	ret.Pattern = s.Pattern
+	ret.MaxItems = s.MaxItems
+	ret.MinItems = s.MinItems
+	ret.UniqueItems = s.UniqueItems
+	ret.MaxProperties = s.MaxProperties
+	ret.MinProperties = s.MinProperties
+	ret.Required = s.Required
+
+	if s.AdditionalProperties != nil {
+		ret.AdditionalProperties = openapiSchema(*s.AdditionalProperties)
+	}
+
+	for _, sub := range s.AllOf {
+		ret.AllOf = append(ret.AllOf, openapiSchema(sub))
+	}
+
+	if s.Nullable {
+		ret.Nullable = true
+		if len(ret.Ref) > 0 {
+			// siblings of $ref are ignored in 3.0, so wrap it
+			ret.AllOf = append(ret.AllOf, &openapiSchemaObject{Ref: ret.Ref})
+			ret.Ref = ""
+		}
+	}
+
+	if s.Properties != nil {
+		props := make(swaggerSchemaObjectProperties, 0, len(*s.Properties))
+		for _, kv := range *s.Properties {
+			if prop, ok := kv.Value.(swaggerSchemaObject); ok {
+				props = append(props, keyVal{Key: kv.Key, Value: openapiSchema(prop)})
+				continue
+			}
+			props = append(props, kv)
+		}
+		ret.Properties = &props
+	}
+
+	return ret
+}
+
+// openapiRef points a swagger 2.0 definition reference at components/schemas.
+func openapiRef(ref string) string {
+	if strings.HasPrefix(ref, swaggerDefinitionRef) {
+		return openapiComponentRef + strings.TrimPrefix(ref, swaggerDefinitionRef)
+	}
+	return ref
+}
+
+// schemaType keeps an empty type out of the output, an empty string stored in
+// the interface would not be dropped by omitempty.
+func schemaType(t string) interface{} {
+	if len(t) == 0 {
+		return nil
+	}
+	return t
+}
+
+func isEmptySchema(s swaggerSchemaObject) bool {
+	return len(s.Ref) == 0 && len(s.Type) == 0 && s.Properties == nil && s.AdditionalProperties == nil
+}
@@ -0,0 +1,86 @@
+package generate
+
+// convertToOpenAPI31 builds the 3.0 document and rewrites every schema into
+// JSON Schema 2020-12, which is what OpenAPI 3.1 uses.
+func convertToOpenAPI31(s *swaggerObject) *openapiObject {
+	o := convertToOpenAPI3(s)
+	o.OpenAPI = openapi31Version
+	o.JSONSchemaDialect = openapi31Dialect
+
+	for _, schema := range o.Components.Schemas {
+		upgradeSchema31(schema)
+	}
+
+	for _, item := range o.Paths {
+		for _, op := range []*openapiOperationObject{item.Get, item.Delete, item.Post, item.Put, item.Patch} {
+			if op == nil {
+				continue
+			}
+
+			for _, param := range op.Parameters {
+				upgradeSchema31(param.Schema)
+			}
+			if op.RequestBody != nil {
+				for _, media := range op.RequestBody.Content {
+					upgradeSchema31(media.Schema)
+				}
+			}
+			for _, resp := range op.Responses {
+				for _, media := range resp.Content {
+					upgradeSchema31(media.Schema)
+				}
+			}
+		}
+	}
+
+	return o
+}
+
+// upgradeSchema31 rewrites the 3.0 only keywords of schema in place:
+//   - nullable becomes a type array such as ["integer","null"]
+//   - example becomes the examples array
+//   - boolean exclusiveMaximum/exclusiveMinimum become numbers
+func upgradeSchema31(schema *openapiSchemaObject) {
+	if schema == nil {
+		return
+	}
+
+	if schema.Nullable {
+		schema.Nullable = false
+		switch {
+		case len(schema.AllOf) > 0:
+			// a nullable $ref, 3.1 allows siblings of $ref so anyOf is enough
+			schema.AnyOf = append(schema.AllOf, &openapiSchemaObject{Type: "null"})
+			schema.AllOf = nil
+		case schema.Type != nil:
+			schema.Type = []string{schema.Type.(string), "null"}
+		}
+	}
+
+	if len(schema.Example) > 0 {
+		schema.Examples = []string{schema.Example}
+		schema.Example = ""
+	}
+
+	if exclusive, ok := schema.ExclusiveMaximum.(bool); ok && exclusive && schema.Maximum != nil {
+		schema.ExclusiveMaximum = *schema.Maximum
+		schema.Maximum = nil
+	}
+	if exclusive, ok := schema.ExclusiveMinimum.(bool); ok && exclusive && schema.Minimum != nil {
+		schema.ExclusiveMinimum = *schema.Minimum
+		schema.Minimum = nil
+	}
+
+	upgradeSchema31(schema.Items)
+	upgradeSchema31(schema.AdditionalProperties)
+	for _, sub := range schema.AllOf {
+		upgradeSchema31(sub)
+	}
+	if schema.Properties != nil {
+		for _, kv := range *schema.Properties {
+			if prop, ok := kv.Value.(*openapiSchemaObject); ok {
+				upgradeSchema31(prop)
+			}
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package generate
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/zeromicro/go-zero/tools/goctl/api/spec"
+)
+
+// Strategies of Options.OperationID, any other value is a template of
+// operationIDPlaceholders.
+const (
+	// OperationIDHandler uses the handler name, which is the default.
+	OperationIDHandler = "handler"
+	// OperationIDGroupHandler prefixes the handler with the group, e.g. userList.
+	OperationIDGroupHandler = "group-handler"
+	// OperationIDMethodPath derives the id from the route, e.g. getApiUserId.
+	OperationIDMethodPath = "method-path"
+)
+
+// operationIDPlaceholders are replaced in operationId templates such as {group}_{handler}.
+var operationIDPlaceholders = []string{"{service}", "{group}", "{handler}", "{method}", "{path}"}
+
+func checkOperationIDStrategy(strategy string) error {
+	switch strategy {
+	case "", OperationIDHandler, OperationIDGroupHandler, OperationIDMethodPath:
+		return nil
+	}
+
+	// a template may only contain the known placeholders
+	rest := strategy
+	for _, placeholder := range operationIDPlaceholders {
+		rest = strings.ReplaceAll(rest, placeholder, "")
+	}
+	if strings.Contains(strategy, "{") && !strings.ContainsAny(rest, "{}") {
+		return nil
+	}
+	return &OptionError{
+		Option:   "operation-id",
+		Value:    strategy,
+		Expected: []string{OperationIDHandler, OperationIDGroupHandler, OperationIDMethodPath, "a template of " + strings.Join(operationIDPlaceholders, " ")},
+	}
+}
+
+// operationID names the operation of route, path is the swagger path with
+// {param} templates.
+func operationID(strategy string, service spec.Service, group spec.Group, route spec.Route, path string) string {
+	groupName := camelCase(strings.Trim(group.GetAnnotation("group"), `"`))
+	switch strategy {
+	case "", OperationIDHandler:
+		return route.Handler
+	case OperationIDGroupHandler:
+		if len(groupName) == 0 {
+			return route.Handler
+		}
+		return groupName + upperFirst(route.Handler)
+	case OperationIDMethodPath:
+		return camelCase(strings.ToLower(route.Method) + " " + path)
+	default:
+		return strings.NewReplacer(
+			"{service}", service.Name,
+			"{group}", groupName,
+			"{handler}", route.Handler,
+			"{method}", strings.ToLower(route.Method),
+			"{path}", camelCase(path),
+		).Replace(strategy)
+	}
+}
+
+// operationIDs tracks the ids in use and the route using them.
+type operationIDs map[string]string
+
+// unique returns id, or id with a numeric suffix if another route already
+// uses it, e.g. list2.
+func (ids operationIDs) unique(id, route string) string {
+	other, ok := ids[id]
+	if !ok {
+		ids[id] = route
+		return id
+	}
+
+	ret := id
+	for i := 2; ; i++ {
+		ret = id + strconv.Itoa(i)
+		if _, ok := ids[ret]; !ok {
+			break
+		}
+	}
+	ids[ret] = route
+	warn(warning{
+		Route:  route,
+		Reason: fmt.Sprintf("operationId %s is already used by %s, renamed to %s", id, other, ret),
+	})
+	return ret
+}
+
+// camelCase joins the words of s, separated by anything but letters and
+// digits, into lowerCamelCase, e.g. /api/user/{id} becomes apiUserId.
+func camelCase(s string) string {
+	words := strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	var b strings.Builder
+	for i, word := range words {
+		if i == 0 {
+			b.WriteString(lowerFirst(word))
+			continue
+		}
+		b.WriteString(upperFirst(word))
+	}
+	return b.String()
+}
+
+func upperFirst(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	if size == 0 {
+		return s
+	}
+	return string(unicode.ToUpper(r)) + s[size:]
+}
+
+func lowerFirst(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	if size == 0 {
+		return s
+	}
+	return string(unicode.ToLower(r)) + s[size:]
+}
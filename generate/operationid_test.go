@@ -0,0 +1,80 @@
+package generate
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCheckOperationIDStrategy(t *testing.T) {
+	tests := []struct {
+		strategy string
+		ok       bool
+	}{
+		{"", true},
+		{OperationIDHandler, true},
+		{OperationIDGroupHandler, true},
+		{OperationIDMethodPath, true},
+		{"{group}_{handler}", true},
+		{"{service}.{method}{path}", true},
+		{"{foo}", false},
+		{"{group}_{foo}", false},
+		{"{group", false},
+		{"x{}", false},
+		{"handlers", false},
+	}
+	for _, tt := range tests {
+		err := checkOperationIDStrategy(tt.strategy)
+		var optErr *OptionError
+		if tt.ok && err != nil {
+			t.Errorf("checkOperationIDStrategy(%q) = %v", tt.strategy, err)
+		}
+		if !tt.ok && !errors.As(err, &optErr) {
+			t.Errorf("checkOperationIDStrategy(%q) = %v, want an OptionError", tt.strategy, err)
+		}
+	}
+}
+
+func TestOperationID(t *testing.T) {
+	in := loadAPI(t, "operationid.api")
+	tests := []struct {
+		strategy string
+		ids      []string
+	}{
+		{OperationIDHandler, []string{"ébaucheItem", "getProfile"}},
+		{OperationIDGroupHandler, []string{"élémentÉbaucheItem", "userProfileGetProfile"}},
+		{OperationIDMethodPath, []string{"getItemsId", "getUsersIdProfile"}},
+		{"{group}_{handler}", []string{"élément_ébaucheItem", "userProfile_getProfile"}},
+		{"{service}.{method}_{path}", []string{"operationid-api.get_itemsId", "operationid-api.get_usersIdProfile"}},
+	}
+	for _, tt := range tests {
+		s, err := build(in, Options{OperationID: tt.strategy})
+		if err != nil {
+			t.Fatalf("%s: %v", tt.strategy, err)
+		}
+		for i, ref := range s.operations {
+			if id := s.Paths[ref.Path].operation(ref.Method).OperationID; id != tt.ids[i] {
+				t.Errorf("%s: operationId of %s %s = %q, want %q", tt.strategy, ref.Method, ref.Path, id, tt.ids[i])
+			}
+		}
+	}
+
+	if _, err := build(in, Options{OperationID: "{foo}"}); err == nil {
+		t.Error("build accepted the unknown placeholder {foo}")
+	}
+}
+
+func TestCamelCase(t *testing.T) {
+	tests := map[string]string{
+		"":               "",
+		"/api/user/{id}": "apiUserId",
+		"get /items":     "getItems",
+		"Élément":        "élément",
+		"user/élément":   "userÉlément",
+		"订单/列表":          "订单列表",
+	}
+	for in, want := range tests {
+		if got := camelCase(in); got != want {
+			t.Errorf("camelCase(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
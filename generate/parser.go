@@ -2,11 +2,12 @@ package generate
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
-	"log"
 	"net/http"
 	"reflect"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"unsafe"
@@ -25,38 +26,62 @@ const (
 	optionsOption   = "options"
 	rangeOption     = "range"
 	exampleOption   = "example"
+	fileOption      = "file"
 	optionSeparator = "|"
 	equalToken      = "="
-	//atRespDoc       = "@respdoc-"
 )
 
-var excludePaths = []string{"/swagger", "/swagger-json"}
+const (
+	mimeMultipartForm  = "multipart/form-data"
+	mimeURLEncodedForm = "application/x-www-form-urlencoded"
+)
+
 var excludeTagKeys = map[string]string{"header": "", "path": "", "form": ""}
 
-func parseRangeOption(option string) (float64, float64, bool) {
-	const str = "\\[([+-]?\\d+(\\.\\d+)?):([+-]?\\d+(\\.\\d+)?)\\]"
+// formMethods are the methods whose form members are submitted as a form,
+// go-zero reads the form members of other methods from the query.
+var formMethods = map[string]bool{http.MethodPost: true, http.MethodPut: true, http.MethodPatch: true}
+
+// rangeBounds are the bounds of a range option, a nil bound is left out.
+type rangeBounds struct {
+	Min, Max                   *float64
+	ExclusiveMin, ExclusiveMax bool
+}
+
+// parseRangeOption parses range=[min:max] of go-zero, either bound may be
+// left out such as [1:], and either may be exclusive such as (0:100].
+func parseRangeOption(option string) (rangeBounds, bool) {
+	const str = "^([\\[(])([+-]?\\d+(\\.\\d+)?)?:([+-]?\\d+(\\.\\d+)?)?([\\])])$"
 	result := regexp.MustCompile(str).FindStringSubmatch(option)
-	if len(result) != 5 {
-		return 0, 0, false
+	if len(result) != 7 || (len(result[2]) == 0 && len(result[4]) == 0) {
+		return rangeBounds{}, false
 	}
 
-	min, err := strconv.ParseFloat(result[1], 64)
-	if err != nil {
-		return 0, 0, false
+	var ret rangeBounds
+	if len(result[2]) > 0 {
+		min, err := strconv.ParseFloat(result[2], 64)
+		if err != nil {
+			return rangeBounds{}, false
+		}
+		ret.Min = &min
+		ret.ExclusiveMin = result[1] == "("
 	}
-
-	max, err := strconv.ParseFloat(result[3], 64)
-	if err != nil {
-		return 0, 0, false
+	if len(result[4]) > 0 {
+		max, err := strconv.ParseFloat(result[4], 64)
+		if err != nil {
+			return rangeBounds{}, false
+		}
+		ret.Max = &max
+		ret.ExclusiveMax = result[6] == ")"
 	}
 
-	if max < min {
-		return min, min, true
+	if ret.Min != nil && ret.Max != nil && *ret.Max < *ret.Min {
+		ret.Max = ret.Min
 	}
-	return min, max, true
+	return ret, true
 }
 
-func applyGenerate(p *plugin.Plugin, host string, basePath string) (*swaggerObject, error) {
+func applyGenerate(p *plugin.Plugin, opt Options, cfg *Config) (*swaggerObject, error) {
 	title, _ := strconv.Unquote(p.Api.Info.Properties["title"])
 	version, _ := strconv.Unquote(p.Api.Info.Properties["version"])
 	desc, _ := strconv.Unquote(p.Api.Info.Properties["desc"])
@@ -75,36 +100,42 @@ func applyGenerate(p *plugin.Plugin, host string, basePath string) (*swaggerObje
 			Description: desc,
 		},
 	}
-	if len(host) > 0 {
-		s.Host = host
+	if len(opt.Host) > 0 {
+		s.Host = opt.Host
 	}
-	if len(basePath) > 0 {
-		s.BasePath = basePath
+	if len(opt.BasePath) > 0 {
+		s.BasePath = opt.BasePath
 	}
 
-	s.SecurityDefinitions = swaggerSecurityDefinitionsObject{}
-	newSecDefValue := swaggerSecuritySchemeObject{}
-	newSecDefValue.Name = "Authorization"
-	newSecDefValue.Description = "Enter JWT Bearer token **_only_**"
-	newSecDefValue.Type = "apiKey"
-	newSecDefValue.In = "header"
-	s.SecurityDefinitions["apiKey"] = newSecDefValue
-	s.Security = append(s.Security, swaggerSecurityRequirementObject{"apiKey": []string{}})
+	// security is only applied to the routes of jwt groups, public routes stay open
+	s.SecurityDefinitions = renderSecurityDefinitions(cfg)
 
 	requestResponseRefs := refMap{}
-	renderServiceRoutes(p.Api.Service, p.Api.Service.Groups, s.Paths, requestResponseRefs)
+	envelopes := map[string]string{}
+	s.operations = renderServiceRoutes(p.Api.Service, p.Api.Service.Groups, StructsByName(p.Api.Types), s.Paths, requestResponseRefs, cfg, envelopes, opt.OperationID)
 	m := messageMap{}
 
-	renderReplyAsDefinition(s.Definitions, m, p.Api.Types, requestResponseRefs)
+	if err := renderReplyAsDefinition(s.Definitions, m, p.Api.Types, requestResponseRefs, opt.Embed); err != nil {
+		return nil, err
+	}
+	if cfg.Envelope != nil {
+		if err := renderEnvelopeDefinitions(s.Definitions, cfg.Envelope, envelopes); err != nil {
+			return nil, err
+		}
+	}
+	dropDanglingResponses(&s)
+	pruneDefinitions(&s, requestResponseRefs, embeddedTypes(p.Api), opt.Prune)
 
 	return &s, nil
 }
 
-func renderServiceRoutes(service spec.Service, groups []spec.Group, paths swaggerPathsObject, requestResponseRefs refMap) {
+func renderServiceRoutes(service spec.Service, groups []spec.Group, types map[string]spec.DefineStruct, paths swaggerPathsObject, requestResponseRefs refMap, cfg *Config, envelopes map[string]string, operationIDStrategy string) []operationRef {
+	var operations []operationRef
+	ids := operationIDs{}
 	//log.Printf("[service]:%+v", service)
 
 	for _, group := range groups {
-		log.Printf("[group]:%+v", group)
+		debugf("render group %v", group.Annotation.Properties)
 		for _, route := range group.Routes {
 			// route:{AtServerAnnotation:{Properties:map[]} Method:get Path:/ RequestType:<nil> ResponseType:{RawName:IndexResponse Members:[{Name:Msg Type:{RawName:string} Tag:`json:"msg"` Comment: Docs:[] IsInline:false}] Docs:[]} Docs:[] Handler:IndexHandler AtDoc:{Properties:map[] Text:"首页"} HandlerDoc:[] HandlerComment:[] Doc:[] Comment:[]}
 			//log.Printf("[route]:%+v", route)
@@ -115,16 +146,22 @@ func renderServiceRoutes(service spec.Service, groups []spec.Group, paths swagge
 			}
 
 			isExclude := false
-			for _, excludePath := range excludePaths {
+			for _, excludePath := range cfg.excludePaths() {
 				if path == excludePath {
 					isExclude = true
 					break
 				}
 			}
 			if isExclude {
+				infof("skip excluded path %s", path)
 				continue
 			}
 			parameters := swaggerParametersObject{}
+			var members []spec.Member
+			if defineStruct, ok := route.RequestType.(spec.DefineStruct); ok {
+				members = FlatMembers(defineStruct, types)
+			}
+			pathMembers := pathMembersOf(members)
 			// 处理路径参数url tag:{path}
 			if countParams(path) > 0 {
 				p := strings.Split(path, "/")
@@ -142,6 +179,20 @@ func renderServiceRoutes(service spec.Service, groups []spec.Group, paths swagge
 							Required: true,
 							Type:     "string",
 						}
+						// 类型,范围,枚举取自请求类型中同名的path字段
+						if member, ok := pathMembers[key]; ok {
+							spo = renderStruct(member, "")
+							spo.Name = key
+							spo.In = "path"
+							spo.Required = true
+							spo.Default = ""
+							if spo.Type == "array" {
+								spo.CollectionFormat = "csv"
+							}
+							delete(pathMembers, key)
+						} else if route.RequestType != nil {
+							debugf("path parameter %s of %s has no path member in %s", key, path, route.RequestType.Name())
+						}
 
 						// extend the comment functionality
 						// to allow query string parameters definitions
@@ -157,7 +208,7 @@ func renderServiceRoutes(service spec.Service, groups []spec.Group, paths swagge
 						//
 
 						prop := route.AtDoc.Properties[key]
-						if prop != "" {
+						if prop != "" && spo.Description == "" {
 							// remove quotes
 							spo.Description = strings.Trim(prop, "\"")
 						}
@@ -166,6 +217,14 @@ func renderServiceRoutes(service spec.Service, groups []spec.Group, paths swagge
 					}
 				}
 			}
+			for _, name := range sortedKeys(pathMembers) {
+				warn(warning{
+					Route:  strings.ToUpper(route.Method) + " " + path,
+					Type:   route.RequestType.Name(),
+					Member: pathMembers[name].Name,
+					Reason: "path member " + name + " does not appear in the route path",
+				})
+			}
 			if defineStruct, ok := route.RequestType.(spec.DefineStruct); ok {
 
 				//处理header
@@ -188,24 +247,36 @@ func renderServiceRoutes(service spec.Service, groups []spec.Group, paths swagge
 								}
 							}*/
 
-				for _, member := range defineStruct.Members {
-					if strings.Contains(member.Tag, "path") {
-						continue
+				// form 字段在post/put/patch请求中作为表单提交, 但swagger 2.0 不允许
+				// body 和 formData 同时出现, 有json字段时仍作为query参数
+				method := strings.ToUpper(route.Method)
+				formIn := "query"
+				if formMethods[method] {
+					if hasBodyMembers(members) {
+						if hasFormMembers(members) {
+							warn(warning{
+								Route:  method + " " + path,
+								Type:   defineStruct.Name(),
+								Reason: "form members are rendered as query parameters next to the json body",
+							})
+						}
+					} else {
+						formIn = "formData"
 					}
-					if strings.Contains(member.Tag, "header") || strings.Contains(member.Tag, "form") {
+				}
 
-						if embedStruct, isEmbed := member.Type.(spec.DefineStruct); isEmbed {
-							for _, m := range embedStruct.Members {
-								parameters = append(parameters, renderStruct(m))
-							}
-							continue
-						}
-						parameters = append(parameters, renderStruct(member))
+				// 内嵌结构体的header和form字段同样是参数, 如 SearchReq{ Paging; Auth }
+				for _, member := range members {
+					if hasPathParameters(member) {
+						continue
+					}
+					if hasHeaderParameters(member) || hasFormParameters(member) {
+						parameters = append(parameters, renderStruct(member, formIn))
 					}
 				}
 
-				//处理非get请求
-				if strings.ToUpper(route.Method) != http.MethodGet {
+				//处理非get请求, 只有json字段才作为body
+				if method != http.MethodGet && hasBodyMembers(members) {
 
 					//post请求也可能出现head
 
@@ -252,10 +323,14 @@ func renderServiceRoutes(service spec.Service, groups []spec.Group, paths swagge
 			}
 
 			desc := "A successful response."
-			respRef := ""
-			if route.ResponseType != nil && len(route.ResponseType.Name()) > 0 {
-				respRef = fmt.Sprintf("#/definitions/%s", route.ResponseType.Name())
+			wrap := func(respType string) string {
+				return responseRef(cfg, group, envelopes, respType)
+			}
+			respType := ""
+			if route.ResponseType != nil {
+				respType = route.ResponseType.Name()
 			}
+			respRef := wrap(respType)
 			tags := service.Name //默认取service的名字
 			if value := group.GetAnnotation("group"); len(value) > 0 {
 				tags = value //group 的名字
@@ -269,23 +344,40 @@ func renderServiceRoutes(service spec.Service, groups []spec.Group, paths swagge
 				Responses: swaggerResponsesObject{
 					"200": swaggerResponseObject{
 						Description: desc,
-						Schema: swaggerSchemaObject{
-							schemaCore: schemaCore{
-								Ref: respRef,
-							},
-						},
 					},
 				},
 			}
+			// 没有响应类型时不生成 schema, 避免空的 $ref
+			if len(respRef) > 0 {
+				resp := operationObject.Responses["200"]
+				resp.Schema = &swaggerSchemaObject{
+					schemaCore: schemaCore{
+						Ref: respRef,
+					},
+				}
+				operationObject.Responses["200"] = resp
+			}
+
+			operationObject.Consumes = consumesOf(parameters)
+			renderRespDocs(operationObject.Responses, route, path, types, cfg, wrap)
 
 			// set OperationID
-			operationObject.OperationID = route.Handler
+			operationObject.OperationID = ids.unique(operationID(operationIDStrategy, service, group, route, path), strings.ToUpper(route.Method)+" "+path)
 
+			// 记录路由用到的类型, 用于裁剪未引用的定义
 			for _, param := range operationObject.Parameters {
 				if param.Schema != nil && param.Schema.Ref != "" {
 					requestResponseRefs[param.Schema.Ref] = struct{}{}
 				}
 			}
+			for _, resp := range operationObject.Responses {
+				if resp.Schema != nil && resp.Schema.Ref != "" {
+					requestResponseRefs[resp.Schema.Ref] = struct{}{}
+				}
+			}
+			if route.RequestType != nil && len(route.RequestType.Name()) > 0 {
+				requestResponseRefs[swaggerDefinitionRef+route.RequestType.Name()] = struct{}{}
+			}
 			operationObject.Summary = strings.ReplaceAll(route.JoinedDoc(), "\"", "")
 
 			if len(route.AtDoc.Properties) > 0 {
@@ -294,9 +386,8 @@ func renderServiceRoutes(service spec.Service, groups []spec.Group, paths swagge
 
 			operationObject.Description = strings.ReplaceAll(operationObject.Description, "\"", "")
 
-			if group.Annotation.Properties["jwt"] != "" {
-				operationObject.Security = &[]swaggerSecurityRequirementObject{{"apiKey": []string{}}}
-			}
+			operationObject.Security = securityOfGroup(cfg, group)
+			renderMiddlewares(operationObject, cfg, group)
 
 			switch strings.ToUpper(route.Method) {
 			case http.MethodGet:
@@ -309,31 +400,66 @@ func renderServiceRoutes(service spec.Service, groups []spec.Group, paths swagge
 				pathItemObject.Put = operationObject
 			case http.MethodPatch:
 				pathItemObject.Patch = operationObject
+			default:
+				warn(warning{
+					Route:  strings.ToUpper(route.Method) + " " + path,
+					Reason: "http method is not supported, route skipped",
+				})
 			}
 
 			paths[path] = pathItemObject
+			if pathItemObject.operation(strings.ToLower(route.Method)) != nil {
+				operations = append(operations, operationRef{Path: path, Method: strings.ToLower(route.Method)})
+			}
 		}
 	}
+	return operations
 }
 
-func renderStruct(member spec.Member) swaggerParameterObject {
-	tempKind := swaggerMapTypes[strings.Replace(member.Type.Name(), "[]", "", -1)]
+// renderStruct renders a header or form member as a parameter, form members
+// go in formIn, which is query or formData.
+func renderStruct(member spec.Member, formIn string) swaggerParameterObject {
+	sp := swaggerParameterObject{In: "query"}
 
-	ftype, format, ok := primitiveSchema(tempKind, member.Type.Name())
-	if !ok {
-		ftype = tempKind.String()
-		format = "UNKNOWN"
+	schema, err := schemaOfType(member.Name, member.Type)
+	switch {
+	case err != nil, len(schema.Ref) > 0, schema.Type == "object":
+		// swagger 2.0 only allows primitives and arrays outside of the body
+		sp.Type = "string"
+		warn(warning{
+			Type:   member.Type.Name(),
+			Member: member.Name,
+			Reason: "parameter type is not a primitive or array, rendered as string",
+		})
+	case schema.Type == "array":
+		sp.Type = schema.Type
+		sp.Items = schema.Items
+		sp.CollectionFormat = "multi"
+	default:
+		sp.Type = schema.Type
+		sp.Format = schema.Format
 	}
 
-	sp := swaggerParameterObject{In: "query", Type: ftype, Format: format}
-	sp.Schema = new(swaggerSchemaObject)
-
 	for _, tag := range member.Tags() {
+		// json, validate 等其他tag与参数无关
+		if _, ok := excludeTagKeys[tag.Key]; !ok {
+			continue
+		}
 		sp.Name = tag.Name //字段名字.
 		// form 字段 作为query参数.此处重要.
-		if strings.Contains(tag.Key, "header") {
+		if tag.Key == "header" {
 			sp.In = tag.Key
 		}
+		if tag.Key == "form" {
+			sp.In = formIn
+		}
+		// form:"avatar,file" 或 []byte 类型的表单字段为上传文件
+		if sp.In == "formData" && (contains(tag.Options, fileOption) || member.Type.Name() == "[]byte") {
+			sp.Type = "file"
+			sp.Format = ""
+			sp.Items = nil
+			sp.CollectionFormat = ""
+		}
 
 		if len(tag.Options) == 0 {
 			sp.Required = true
@@ -352,10 +478,9 @@ func renderStruct(member spec.Member) swaggerParameterObject {
 			if strings.HasPrefix(option, rangeOption) {
 				segs := strings.SplitN(option, equalToken, 2)
 				if len(segs) == 2 {
-					min, max, ok := parseRangeOption(segs[1])
-					if ok {
-						sp.Schema.Minimum = min
-						sp.Schema.Maximum = max
+					if bounds, ok := parseRangeOption(segs[1]); ok {
+						sp.Minimum, sp.ExclusiveMinimum = bounds.Min, bounds.ExclusiveMin
+						sp.Maximum, sp.ExclusiveMaximum = bounds.Max, bounds.ExclusiveMax
 					}
 				}
 			}
@@ -380,91 +505,258 @@ func renderStruct(member spec.Member) swaggerParameterObject {
 	}
 
 	if len(member.Comment) > 0 {
-		sp.Description = strings.TrimLeft(member.Comment, "//")
+		sp.Description = strings.TrimSpace(strings.TrimLeft(member.Comment, "//"))
 	}
 
 	return sp
 }
 
-func renderReplyAsDefinition(d swaggerDefinitionsObject, m messageMap, p []spec.Type, refs refMap) {
+func renderReplyAsDefinition(d swaggerDefinitionsObject, m messageMap, p []spec.Type, refs refMap, embed string) error {
+	types := StructsByName(p)
 	for _, i2 := range p {
 		schema := swaggerSchemaObject{
 			schemaCore: schemaCore{
 				Type: "object",
 			},
 		}
-		defineStruct, _ := i2.(spec.DefineStruct)
+		defineStruct, ok := i2.(spec.DefineStruct)
+		if !ok {
+			warn(warning{Type: i2.Name(), Reason: "only structs are rendered as definitions, type skipped"})
+			continue
+		}
+
+		//{Name:Who Type:{RawName:string} Tag:`path:"who"` Comment: Docs:[] IsInline:false}
+
+		seen := map[string]bool{defineStruct.Name(): true}
+		parents, err := renderMembers(&schema, defineStruct, types, embed, seen)
+		if err != nil {
+			return err
+		}
+
+		// allOf: [{$ref: Embedded}, {own properties}]
+		if len(parents) > 0 {
+			if schema.Properties != nil {
+				parents = append(parents, schema)
+			}
+			schema = swaggerSchemaObject{AllOf: parents}
+		}
 
 		schema.Title = defineStruct.Name() //结构体的名字
+		d[i2.Name()] = schema
+	}
 
-		//{Name:Who Type:{RawName:string} Tag:`path:"who"` Comment: Docs:[] IsInline:false}
+	return nil
+}
+
+// renderMembers adds the body members of s to schema. Embedded structs are
+// flattened recursively, or returned as references with EmbedAllOf. seen
+// holds the structs being rendered to stop on cyclic embeddings.
+func renderMembers(schema *swaggerSchemaObject, s spec.DefineStruct, types map[string]spec.DefineStruct, embed string, seen map[string]bool) ([]swaggerSchemaObject, error) {
+	// 外层字段覆盖内嵌结构体的同名字段, 与 go 的字段提升规则一致
+	own := map[string]bool{}
+	for _, member := range s.Members {
+		if !member.IsInline {
+			own[propertyName(member)] = true
+		}
+	}
 
-		for _, member := range defineStruct.Members {
+	var parents []swaggerSchemaObject
+	for _, member := range s.Members {
 
-			//header path form 不在出现在http body里面
+		//header path form 不在出现在http body里面
 
-			if hasExcluParameters(member) {
+		if hasExcluParameters(member) {
+			continue
+		}
+
+		if member.IsInline {
+			embedded, ok := ResolveStruct(member.Type, types)
+			if !ok {
+				warn(warning{
+					Type:   s.Name(),
+					Member: member.Type.Name(),
+					Reason: "embedded member is not a struct, member skipped",
+				})
 				continue
 			}
-
-			kv := keyVal{Value: schemaOfField(member)}
-			kv.Key = member.Name
-			if tag, err := member.GetPropertyName(); err == nil {
-				kv.Key = tag
+			if seen[embedded.Name()] {
+				warn(warning{
+					Type:   s.Name(),
+					Member: embedded.Name(),
+					Reason: "cyclic embedding, member skipped",
+				})
+				continue
 			}
-			if kv.Key == "" {
-				memberStruct, _ := member.Type.(spec.DefineStruct)
-				for _, m := range memberStruct.Members {
-					if strings.Contains(m.Tag, "header") {
-						continue
-					}
 
-					mkv := keyVal{
-						Value: schemaOfField(m),
-						Key:   m.Name,
-					}
-
-					if tag, err := m.GetPropertyName(); err == nil {
-						mkv.Key = tag
-					}
-					if schema.Properties == nil {
-						schema.Properties = &swaggerSchemaObjectProperties{}
-					}
-					*schema.Properties = append(*schema.Properties, mkv)
-				}
+			if embed == EmbedAllOf {
+				parents = append(parents, swaggerSchemaObject{schemaCore: schemaCore{Ref: swaggerDefinitionRef + embedded.Name()}})
 				continue
 			}
-			if schema.Properties == nil {
-				schema.Properties = &swaggerSchemaObjectProperties{}
+
+			seen[embedded.Name()] = true
+			inner := swaggerSchemaObject{}
+			if _, err := renderMembers(&inner, embedded, types, embed, seen); err != nil {
+				return nil, err
 			}
-			*schema.Properties = append(*schema.Properties, kv)
+			delete(seen, embedded.Name())
 
-			for _, tag := range member.Tags() {
-				if len(tag.Options) == 0 {
-					if !contains(schema.Required, tag.Name) && tag.Name != "required" {
-						schema.Required = append(schema.Required, tag.Name)
-					}
+			if inner.Properties == nil {
+				continue
+			}
+			for _, kv := range *inner.Properties {
+				if own[kv.Key] || hasProperty(schema, kv.Key) {
 					continue
 				}
+				addProperty(schema, kv)
+				if contains(inner.Required, kv.Key) {
+					schema.Required = append(schema.Required, kv.Key)
+				}
+			}
+			continue
+		}
 
-				required := true
-				for _, option := range tag.Options {
-					// case strings.HasPrefix(option, defaultOption):
-					// case strings.HasPrefix(option, optionsOption):
+		fieldSchema, err := schemaOfField(member)
+		if err != nil {
+			return nil, withStruct(err, s.Name())
+		}
+		addProperty(schema, keyVal{Key: propertyName(member), Value: fieldSchema})
 
-					if strings.HasPrefix(option, optionalOption) || strings.HasPrefix(option, omitemptyOption) {
-						required = false
-					}
+		for _, tag := range member.Tags() {
+			if len(tag.Options) == 0 {
+				if !contains(schema.Required, tag.Name) && tag.Name != "required" {
+					schema.Required = append(schema.Required, tag.Name)
 				}
+				continue
+			}
 
-				if required && !contains(schema.Required, tag.Name) {
-					schema.Required = append(schema.Required, tag.Name)
+			required := true
+			for _, option := range tag.Options {
+				// case strings.HasPrefix(option, defaultOption):
+				// case strings.HasPrefix(option, optionsOption):
+
+				if strings.HasPrefix(option, optionalOption) || strings.HasPrefix(option, omitemptyOption) {
+					required = false
 				}
 			}
+
+			if required && !contains(schema.Required, tag.Name) {
+				schema.Required = append(schema.Required, tag.Name)
+			}
+		}
+	}
+
+	return parents, nil
+}
+
+// propertyName is the json name of member, its go name without a json tag.
+func propertyName(member spec.Member) string {
+	if tag, err := member.GetPropertyName(); err == nil {
+		return tag
+	}
+	return member.Name
+}
+
+func hasProperty(schema *swaggerSchemaObject, key string) bool {
+	if schema.Properties == nil {
+		return false
+	}
+	for _, kv := range *schema.Properties {
+		if kv.Key == key {
+			return true
 		}
+	}
+	return false
+}
 
-		d[i2.Name()] = schema
+func addProperty(schema *swaggerSchemaObject, kv keyVal) {
+	if schema.Properties == nil {
+		schema.Properties = &swaggerSchemaObjectProperties{}
 	}
+	*schema.Properties = append(*schema.Properties, kv)
+}
+
+// StructsByName indexes the declared structs, the structs referred to by
+// members of other types are parsed without their members.
+func StructsByName(types []spec.Type) map[string]spec.DefineStruct {
+	ret := map[string]spec.DefineStruct{}
+	for _, t := range types {
+		if s, ok := t.(spec.DefineStruct); ok {
+			ret[s.Name()] = s
+		}
+	}
+	return ret
+}
+
+// ResolveStruct returns the declaration of the struct t refers to, t may be
+// a pointer to it.
+func ResolveStruct(t spec.Type, types map[string]spec.DefineStruct) (spec.DefineStruct, bool) {
+	if ptr, ok := t.(spec.PointerType); ok {
+		t = ptr.Type
+	}
+	s, ok := t.(spec.DefineStruct)
+	if !ok {
+		return s, false
+	}
+	if declared, ok := types[s.Name()]; ok {
+		return declared, true
+	}
+	return s, true
+}
+
+// FlatMembers returns the members of s with its embedded structs, looked up
+// in the index of StructsByName, replaced by their members, recursively.
+// Members of s shadow the promoted members of the same name as in go, and a
+// cyclic embedding is skipped with a warning.
+func FlatMembers(s spec.DefineStruct, types map[string]spec.DefineStruct) []spec.Member {
+	return walkMembers(s, types, map[string]bool{s.Name(): true})
+}
+
+func walkMembers(s spec.DefineStruct, types map[string]spec.DefineStruct, seen map[string]bool) []spec.Member {
+	own := map[string]bool{}
+	for _, member := range s.Members {
+		if !member.IsInline {
+			own[member.Name] = true
+		}
+	}
+
+	var ret []spec.Member
+	for _, member := range s.Members {
+		if !member.IsInline {
+			ret = append(ret, member)
+			continue
+		}
+
+		embedded, ok := ResolveStruct(member.Type, types)
+		if !ok {
+			continue
+		}
+		if seen[embedded.Name()] {
+			warn(warning{
+				Type:   s.Name(),
+				Member: embedded.Name(),
+				Reason: "cyclic embedding, member skipped",
+			})
+			continue
+		}
+
+		seen[embedded.Name()] = true
+		for _, m := range walkMembers(embedded, types, seen) {
+			if !own[m.Name] {
+				ret = append(ret, m)
+			}
+		}
+		delete(seen, embedded.Name())
+	}
+	return ret
+}
+
+// withStruct records the declaring type on an UnsupportedTypeError.
+func withStruct(err error, name string) error {
+	var typeErr *UnsupportedTypeError
+	if errors.As(err, &typeErr) && len(typeErr.Struct) == 0 {
+		typeErr.Struct = name
+	}
+	return err
 }
 
 func hasExcluParameters(member spec.Member) bool {
@@ -476,6 +768,68 @@ func hasExcluParameters(member spec.Member) bool {
 
 	return false
 }
+
+// pathMembersOf maps the path tag names of members, flattened by
+// FlatMembers, to their members, such as those of an embedded IDRequest.
+func pathMembersOf(members []spec.Member) map[string]spec.Member {
+	ret := map[string]spec.Member{}
+	for _, member := range members {
+		for _, tag := range member.Tags() {
+			if tag.Key == "path" {
+				ret[tag.Name] = member
+			}
+		}
+	}
+	return ret
+}
+
+func sortedKeys(m map[string]spec.Member) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// hasBodyMembers reports whether members, flattened by FlatMembers, has
+// members sent in the json body.
+func hasBodyMembers(members []spec.Member) bool {
+	for _, member := range members {
+		if !hasExcluParameters(member) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasFormMembers reports whether members, flattened by FlatMembers, has form
+// members.
+func hasFormMembers(members []spec.Member) bool {
+	for _, member := range members {
+		if hasFormParameters(member) {
+			return true
+		}
+	}
+	return false
+}
+
+// consumesOf returns the media types of an operation with form parameters,
+// nil to use the json default of the document.
+func consumesOf(params swaggerParametersObject) []string {
+	consumes := []string(nil)
+	for _, p := range params {
+		if p.In != "formData" {
+			continue
+		}
+		if p.Type == "file" {
+			return []string{mimeMultipartForm}
+		}
+		consumes = []string{mimeURLEncodedForm}
+	}
+	return consumes
+}
+
 func hasPathParameters(member spec.Member) bool {
 	for _, tag := range member.Tags() {
 		if tag.Key == "path" {
@@ -486,6 +840,16 @@ func hasPathParameters(member spec.Member) bool {
 	return false
 }
 
+func hasFormParameters(member spec.Member) bool {
+	for _, tag := range member.Tags() {
+		if tag.Key == "form" {
+			return true
+		}
+	}
+
+	return false
+}
+
 func hasHeaderParameters(member spec.Member) bool {
 	for _, tag := range member.Tags() {
 		if tag.Key == "header" {
@@ -496,99 +860,21 @@ func hasHeaderParameters(member spec.Member) bool {
 	return false
 }
 
-func schemaOfField(member spec.Member) swaggerSchemaObject {
+func schemaOfField(member spec.Member) (swaggerSchemaObject, error) {
 	////{Name:Who Type:{RawName:string} Tag:`path:"who"` Comment: Docs:[] IsInline:false}
-	ret := swaggerSchemaObject{}
-
-	var core schemaCore
-	// spew.Dump(member)
-	kind := swaggerMapTypes[member.Type.Name()]
-	var props *swaggerSchemaObjectProperties
-
 	comment := member.GetComment()
 	comment = strings.Replace(comment, "//", "", -1)
 
-	switch ft := kind; ft {
-	case reflect.Invalid: //[]Struct 也有可能是 Struct
-		// []Struct
-		// map[ArrayType:map[Star:map[StringExpr:UserSearchReq] StringExpr:*UserSearchReq] StringExpr:[]*UserSearchReq]
-		refTypeName := strings.Replace(member.Type.Name(), "[", "", 1)
-		refTypeName = strings.Replace(refTypeName, "]", "", 1)
-		refTypeName = strings.Replace(refTypeName, "*", "", 1)
-		refTypeName = strings.Replace(refTypeName, "{", "", 1)
-		refTypeName = strings.Replace(refTypeName, "}", "", 1)
-		// interface
-
-		if refTypeName == "interface" {
-			core = schemaCore{Type: "object"}
-		} else if refTypeName == "mapstringstring" {
-			core = schemaCore{Type: "object"}
-		} else if strings.HasPrefix(refTypeName, "[]") {
-			core = schemaCore{Type: "array"}
-
-			tempKind := swaggerMapTypes[strings.Replace(refTypeName, "[]", "", -1)]
-			ftype, format, ok := primitiveSchema(tempKind, refTypeName)
-			if ok {
-				core.Items = &swaggerItemsObject{Type: ftype, Format: format}
-			} else {
-				core.Items = &swaggerItemsObject{Type: ft.String(), Format: "UNKNOWN"}
-			}
-
-		} else {
-			core = schemaCore{
-				Ref: "#/definitions/" + refTypeName,
-			}
-		}
-	case reflect.Slice:
-		tempKind := swaggerMapTypes[strings.Replace(member.Type.Name(), "[]", "", -1)]
-		ftype, format, ok := primitiveSchema(tempKind, member.Type.Name())
-
-		if ok {
-			core = schemaCore{Type: ftype, Format: format}
-		} else {
-			core = schemaCore{Type: ft.String(), Format: "UNKNOWN"}
-		}
-	default:
-		ftype, format, ok := primitiveSchema(ft, member.Type.Name())
-		if ok {
-			core = schemaCore{Type: ftype, Format: format}
-		} else {
-			core = schemaCore{Type: ft.String(), Format: "UNKNOWN"}
+	ret, err := schemaOfType(member.Name, member.Type)
+	if err != nil {
+		var typeErr *UnsupportedTypeError
+		if errors.As(err, &typeErr) {
+			typeErr.Member = member.Name
+			typeErr.Type = member.Type.Name()
 		}
+		return ret, err
 	}
 
-	switch ft := kind; ft {
-	case reflect.Slice:
-		ret = swaggerSchemaObject{
-			schemaCore: schemaCore{
-				Type:  "array",
-				Items: (*swaggerItemsObject)(&core),
-			},
-		}
-	case reflect.Invalid:
-		// 判断是否数组
-		if strings.HasPrefix(member.Type.Name(), "[]") {
-			ret = swaggerSchemaObject{
-				schemaCore: schemaCore{
-					Type:  "array",
-					Items: (*swaggerItemsObject)(&core),
-				},
-			}
-		} else {
-			ret = swaggerSchemaObject{
-				schemaCore: core,
-				Properties: props,
-			}
-		}
-		if strings.HasPrefix(member.Type.Name(), "map") {
-			fmt.Println("暂不支持map类型")
-		}
-	default:
-		ret = swaggerSchemaObject{
-			schemaCore: core,
-			Properties: props,
-		}
-	}
 	ret.Description = comment
 
 	for _, tag := range member.Tags() {
@@ -610,10 +896,9 @@ func schemaOfField(member spec.Member) swaggerSchemaObject {
 			case strings.HasPrefix(option, rangeOption):
 				segs := strings.SplitN(option, equalToken, 2)
 				if len(segs) == 2 {
-					min, max, ok := parseRangeOption(segs[1])
-					if ok {
-						ret.Minimum = min
-						ret.Maximum = max
+					if bounds, ok := parseRangeOption(segs[1]); ok {
+						ret.Minimum, ret.ExclusiveMinimum = bounds.Min, bounds.ExclusiveMin
+						ret.Maximum, ret.ExclusiveMaximum = bounds.Max, bounds.ExclusiveMax
 					}
 				}
 			case strings.HasPrefix(option, exampleOption):
@@ -625,11 +910,64 @@ func schemaOfField(member spec.Member) swaggerSchemaObject {
 		}
 	}
 
-	return ret
+	return ret, nil
+}
+
+// schemaOfType walks the goctl type of member recursively and builds the
+// matching schema, e.g. [][]int, []*Foo, []map[string]Bar or *[]Foo.
+func schemaOfType(member string, t spec.Type) (swaggerSchemaObject, error) {
+	switch v := t.(type) {
+	case spec.PrimitiveType:
+		ftype, format, ok := primitiveSchema(swaggerMapTypes[v.Name()])
+		if !ok {
+			return swaggerSchemaObject{}, &UnsupportedTypeError{Type: v.Name(), Reason: "unknown primitive type"}
+		}
+		return swaggerSchemaObject{schemaCore: schemaCore{Type: ftype, Format: format}}, nil
+	case spec.DefineStruct:
+		return swaggerSchemaObject{schemaCore: schemaCore{Ref: swaggerDefinitionRef + v.Name()}}, nil
+	case spec.MapType:
+		// json object keys are always strings, only the value type matters
+		value, err := schemaOfType(member, v.Value)
+		if err != nil {
+			return swaggerSchemaObject{}, err
+		}
+		return swaggerSchemaObject{
+			schemaCore:           schemaCore{Type: "object"},
+			AdditionalProperties: &value,
+		}, nil
+	case spec.ArrayType:
+		if p, ok := v.Value.(spec.PrimitiveType); ok && p.Name() == "byte" {
+			// encoding/json encodes []byte as a base64 string
+			return swaggerSchemaObject{schemaCore: schemaCore{Type: "string", Format: "byte"}}, nil
+		}
+
+		items, err := schemaOfType(member, v.Value)
+		if err != nil {
+			return swaggerSchemaObject{}, err
+		}
+		return swaggerSchemaObject{
+			schemaCore: schemaCore{
+				Type:  "array",
+				Items: (*swaggerItemsObject)(&items),
+			},
+		}, nil
+	case spec.PointerType:
+		schema, err := schemaOfType(member, v.Type)
+		if err != nil {
+			return swaggerSchemaObject{}, err
+		}
+		schema.Nullable = true
+		return schema, nil
+	case spec.InterfaceType:
+		warn(warning{Type: v.Name(), Member: member, Reason: "interface rendered as free-form object"})
+		return swaggerSchemaObject{schemaCore: schemaCore{Type: "object"}}, nil
+	default:
+		return swaggerSchemaObject{}, &UnsupportedTypeError{Type: t.Name(), Reason: "unknown type"}
+	}
 }
 
 // https://swagger.io/specification/ Data Types
-func primitiveSchema(kind reflect.Kind, t string) (ftype, format string, ok bool) {
+func primitiveSchema(kind reflect.Kind) (ftype, format string, ok bool) {
 	switch kind {
 	case reflect.Int:
 		return "integer", "int32", true
@@ -642,7 +980,7 @@ func primitiveSchema(kind reflect.Kind, t string) (ftype, format string, ok bool
 	case reflect.Int16:
 		return "integer", "int16", true
 	case reflect.Uint16:
-		return "integer", "uin16", true
+		return "integer", "uint16", true
 	case reflect.Int64:
 		return "integer", "int64", true
 	case reflect.Uint64:
@@ -655,8 +993,6 @@ func primitiveSchema(kind reflect.Kind, t string) (ftype, format string, ok bool
 		return "number", "float", true
 	case reflect.Float64:
 		return "number", "double", true
-	case reflect.Slice:
-		return strings.Replace(t, "[]", "", -1), "", true
 	default:
 		return "", "", false
 	}
@@ -739,11 +1075,11 @@ func parserTags(option string, ret *swaggerSchemaObject) {
 		num1, num2 := rangeArray[0][1:], rangeArray[1][:len(rangeArray[1])-1]
 		float1, err := strconv.ParseFloat(num1, 64)
 		if err == nil {
-			ret.Minimum = float1
+			ret.Minimum = &float1
 		}
 		float2, err := strconv.ParseFloat(num2, 64)
 		if err == nil {
-			ret.Maximum = float2
+			ret.Maximum = &float2
 		}
 	}
 }
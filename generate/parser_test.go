@@ -0,0 +1,184 @@
+package generate
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEmbeddedParameters(t *testing.T) {
+	s, err := build(loadAPI(t, "embed.api"), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path, method string
+		params       []string
+		consumes     []string
+	}{
+		{"/search", "get", []string{"page query", "size query", "X-Token header", "keyword query"}, nil},
+		{"/search", "post", []string{"page formData", "size formData", "X-Token header", "keyword formData"}, []string{mimeURLEncodedForm}},
+		{"/comments", "post", []string{"page query", "size query", "body body"}, nil},
+		{"/search", "delete", []string{"page query", "size query", "X-Token header", "keyword query"}, nil},
+		{"/devices/{id}", "put", []string{"id path", "body body"}, nil},
+	}
+	for _, tt := range tests {
+		op := s.Paths[tt.path].operation(tt.method)
+		var params []string
+		for _, p := range op.Parameters {
+			params = append(params, p.Name+" "+p.In)
+		}
+		if !reflect.DeepEqual(params, tt.params) {
+			t.Errorf("%s %s parameters = %v, want %v", tt.method, tt.path, params, tt.params)
+		}
+		if !reflect.DeepEqual(op.Consumes, tt.consumes) {
+			t.Errorf("%s %s consumes = %v, want %v", tt.method, tt.path, op.Consumes, tt.consumes)
+		}
+	}
+}
+
+func TestFlatMembers(t *testing.T) {
+	tests := []struct {
+		file, typ string
+		members   []string
+		body      bool
+		form      bool
+	}{
+		{"embed.api", "SearchReq", []string{"Page", "Size", "Token", "Keyword"}, false, true},
+		{"embed.api", "CommentReq", []string{"Page", "Size", "Content"}, true, true},
+		{"embed.api", "Auth", []string{"Token"}, false, false},
+		{"cyclic.api", "Node", []string{"Depth", "ID", "Name"}, false, true},
+		{"cyclic.api", "Tree", []string{"ID", "Name", "Depth"}, false, true},
+	}
+	for _, tt := range tests {
+		types := StructsByName(loadAPI(t, tt.file).Api.Types)
+		var members []string
+		log := captureLog(t, func() {
+			flat := FlatMembers(types[tt.typ], types)
+			for _, m := range flat {
+				members = append(members, m.Name)
+			}
+			if got := hasBodyMembers(flat); got != tt.body {
+				t.Errorf("hasBodyMembers(%s) = %v, want %v", tt.typ, got, tt.body)
+			}
+			if got := hasFormMembers(flat); got != tt.form {
+				t.Errorf("hasFormMembers(%s) = %v, want %v", tt.typ, got, tt.form)
+			}
+		})
+		if !reflect.DeepEqual(members, tt.members) {
+			t.Errorf("FlatMembers(%s) = %v, want %v", tt.typ, members, tt.members)
+		}
+		if cyclic := strings.Contains(log, "cyclic embedding"); cyclic != (tt.file == "cyclic.api") {
+			t.Errorf("FlatMembers(%s) logged %q", tt.typ, log)
+		}
+	}
+}
+
+func TestCyclicEmbedding(t *testing.T) {
+	in := loadAPI(t, "cyclic.api")
+	for _, spec := range []string{SpecSwagger2, SpecOpenAPI3, SpecOpenAPI31} {
+		for _, embed := range []string{EmbedFlatten, EmbedAllOf} {
+			log := captureLog(t, func() {
+				if _, err := Render(in, Options{Spec: spec, Embed: embed}); err != nil {
+					t.Errorf("%s %s: %v", spec, embed, err)
+				}
+			})
+			if !strings.Contains(log, "cyclic embedding") {
+				t.Errorf("%s %s: no cyclic embedding warning in %q", spec, embed, log)
+			}
+		}
+	}
+
+	s, err := build(in, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := s.Paths["/nodes/{id}"].Get.Parameters[0]
+	if id.Name != "id" || id.In != "path" || id.Type != "integer" {
+		t.Errorf("path parameter = %+v, want the integer id member", id)
+	}
+}
+
+func TestParseRangeOption(t *testing.T) {
+	tests := []struct {
+		option string
+		want   string
+		ok     bool
+	}{
+		{"[1:10]", "[1, 10]", true},
+		{"(0:100]", "(0, 100]", true},
+		{"[0:1)", "[0, 1)", true},
+		{"(0:)", "> 0", true},
+		{"[:5)", "< 5", true},
+		{"[0:]", ">= 0", true},
+		{"[10:1]", "[10, 10]", true},
+		{"[:]", "", false},
+		{"1:10", "", false},
+	}
+	for _, tt := range tests {
+		bounds, ok := parseRangeOption(tt.option)
+		got := rangeOf(bounds.Min, bounds.Max, bounds.ExclusiveMin, bounds.ExclusiveMax)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("parseRangeOption(%q) = %q, %v, want %q, %v", tt.option, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestExclusiveRange(t *testing.T) {
+	s, err := build(loadAPI(t, "user.api"), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var score *swaggerParameterObject
+	for i, p := range s.Paths["/api/user/search"].Get.Parameters {
+		if p.Name == "score" {
+			score = &s.Paths["/api/user/search"].Get.Parameters[i]
+		}
+	}
+	if score == nil || score.Minimum == nil || *score.Minimum != 0 || !score.ExclusiveMinimum ||
+		score.Maximum == nil || *score.Maximum != 100 || score.ExclusiveMaximum {
+		t.Errorf("score parameter = %+v, want (0, 100]", score)
+	}
+
+	rate := s.Definitions["UserInfoReply"].Properties
+	var schema swaggerSchemaObject
+	for _, kv := range *rate {
+		if kv.Key == "rate" {
+			schema = kv.Value.(swaggerSchemaObject)
+		}
+	}
+	if got := rangeOf(schema.Minimum, schema.Maximum, schema.ExclusiveMinimum, schema.ExclusiveMaximum); got != "(0, 1)" {
+		t.Errorf("rate range = %q, want (0, 1)", got)
+	}
+
+	o := convertToOpenAPI31(s)
+	found := false
+	for _, kv := range *o.Components.Schemas["UserInfoReply"].Properties {
+		if kv.Key != "rate" {
+			continue
+		}
+		found = true
+		got := kv.Value.(*openapiSchemaObject)
+		if got.Minimum != nil || got.Maximum != nil || got.ExclusiveMinimum != 0.0 || got.ExclusiveMaximum != 1.0 {
+			t.Errorf("3.1 rate = %v, %v, %v, %v, want exclusiveMinimum 0 and exclusiveMaximum 1",
+				got.Minimum, got.Maximum, got.ExclusiveMinimum, got.ExclusiveMaximum)
+		}
+	}
+	if !found {
+		t.Error("3.1 UserInfoReply has no rate property")
+	}
+}
+
+func TestPathParameterOptions(t *testing.T) {
+	s, err := build(loadAPI(t, "user.api"), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	params := s.Paths["/api/user/{id}"].Get.Parameters
+	if len(params) != 1 || params[0].In != "path" || params[0].Minimum == nil || *params[0].Minimum != 1 {
+		t.Errorf("id parameter = %+v, want a path parameter with minimum 1", params)
+	}
+}
@@ -0,0 +1,237 @@
+package generate
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strings"
+
+	plugin2 "github.com/zeromicro/go-zero/tools/goctl/plugin"
+)
+
+const (
+	postmanSchema = "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+	// defaultHost is the listen address of a go-zero service created by goctl.
+	defaultHost = "localhost:8888"
+	// tokenVariable holds the value of the apiKey security schemes.
+	tokenVariable = "token"
+)
+
+// https://schema.getpostman.com/json/collection/v2.1.0/docs/index.html
+type postmanCollection struct {
+	Info     postmanInfo       `json:"info"`
+	Item     []postmanFolder   `json:"item"`
+	Variable []postmanVariable `json:"variable"`
+}
+
+type postmanInfo struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Schema      string `json:"schema"`
+}
+
+type postmanFolder struct {
+	Name string        `json:"name"`
+	Item []postmanItem `json:"item"`
+}
+
+type postmanItem struct {
+	Name    string         `json:"name"`
+	Request postmanRequest `json:"request"`
+}
+
+type postmanRequest struct {
+	Method      string            `json:"method"`
+	Header      []postmanVariable `json:"header"`
+	URL         postmanURL        `json:"url"`
+	Body        *postmanBody      `json:"body,omitempty"`
+	Description string            `json:"description,omitempty"`
+}
+
+type postmanURL struct {
+	Raw      string            `json:"raw"`
+	Protocol string            `json:"protocol"`
+	Host     []string          `json:"host"`
+	Path     []string          `json:"path"`
+	Query    []postmanVariable `json:"query,omitempty"`
+	Variable []postmanVariable `json:"variable,omitempty"`
+}
+
+type postmanBody struct {
+	Mode       string            `json:"mode"`
+	Raw        string            `json:"raw,omitempty"`
+	FormData   []postmanVariable `json:"formdata,omitempty"`
+	URLEncoded []postmanVariable `json:"urlencoded,omitempty"`
+	Options    interface{}       `json:"options,omitempty"`
+}
+
+// postmanVariable is a key and value pair, it is used for the collection
+// variables, headers, query and path parameters and form fields.
+type postmanVariable struct {
+	Key         string `json:"key"`
+	Value       string `json:"value"`
+	Type        string `json:"type,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+var pathTemplatePattern = regexp.MustCompile(`\{([^{}/]+)\}`)
+
+// DoPostman writes the Postman v2.1 collection of in.
+func DoPostman(in *plugin2.Plugin, opt Options) error {
+	content, err := RenderPostman(in, opt)
+	if err != nil {
+		return err
+	}
+	return write(in, opt.Filename, content)
+}
+
+// RenderPostman generates a Postman v2.1 collection with a folder per tag, the
+// group or swtags of the routes. The host and the base path are collection
+// variables, the security schemes use the token variable.
+func RenderPostman(in *plugin2.Plugin, opt Options) ([]byte, error) {
+	s, err := build(in, opt)
+	if err != nil {
+		return nil, err
+	}
+
+	name := s.Info.Title
+	if len(name) == 0 {
+		name = in.Api.Service.Name
+	}
+	host := s.Host
+	if len(host) == 0 {
+		host = defaultHost
+	}
+	collection := postmanCollection{
+		Info: postmanInfo{Name: name, Description: s.Info.Description, Schema: postmanSchema},
+		Variable: []postmanVariable{
+			{Key: "host", Value: host},
+			{Key: "basePath", Value: s.BasePath},
+			{Key: tokenVariable, Value: ""},
+		},
+	}
+
+	for _, tag := range operationsByTag(s) {
+		folder := postmanFolder{Name: tag.Tag}
+		for _, ref := range tag.Operations {
+			item, err := postmanItemOf(s, ref, s.Paths[ref.Path].operation(ref.Method))
+			if err != nil {
+				return nil, &WriteError{Path: opt.Filename, Err: err}
+			}
+			folder.Item = append(folder.Item, item)
+		}
+		collection.Item = append(collection.Item, folder)
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(collection); err != nil {
+		return nil, &WriteError{Path: opt.Filename, Err: err}
+	}
+	return buf.Bytes(), nil
+}
+
+func postmanItemOf(s *swaggerObject, ref operationRef, op *swaggerOperationObject) (postmanItem, error) {
+	path := pathTemplatePattern.ReplaceAllString(ref.Path, ":$1")
+	request := postmanRequest{
+		Method:      strings.ToUpper(ref.Method),
+		Header:      []postmanVariable{},
+		Description: op.Description,
+		URL: postmanURL{
+			Raw:      "http://{{host}}{{basePath}}" + path,
+			Protocol: "http",
+			Host:     []string{"{{host}}{{basePath}}"},
+			Path:     strings.Split(strings.TrimPrefix(path, "/"), "/"),
+		},
+	}
+
+	var form []postmanVariable
+	for _, param := range op.Parameters {
+		v := postmanVariable{Key: param.Name, Value: parameterExample(param), Description: param.Description}
+		switch param.In {
+		case "path":
+			request.URL.Variable = append(request.URL.Variable, v)
+		case "query":
+			request.URL.Query = append(request.URL.Query, v)
+		case "header":
+			request.Header = append(request.Header, v)
+		case "formData":
+			v.Type = "text"
+			if param.Type == "file" {
+				v.Type = "file"
+			}
+			form = append(form, v)
+		case "body":
+			if param.Schema == nil {
+				continue
+			}
+			body, err := exampleJSON(*param.Schema, s.Definitions)
+			if err != nil {
+				return postmanItem{}, err
+			}
+			request.Header = append(request.Header, postmanVariable{Key: "Content-Type", Value: "application/json"})
+			request.Body = &postmanBody{
+				Mode:    "raw",
+				Raw:     body,
+				Options: map[string]interface{}{"raw": map[string]string{"language": "json"}},
+			}
+		}
+	}
+	if len(form) > 0 {
+		if contains(op.Consumes, mimeMultipartForm) {
+			request.Body = &postmanBody{Mode: "formdata", FormData: form}
+		} else {
+			request.Body = &postmanBody{Mode: "urlencoded", URLEncoded: form}
+		}
+	}
+
+	for _, scheme := range securitySchemesOf(s, op) {
+		v := postmanVariable{Key: scheme.Name, Value: "{{" + tokenVariable + "}}", Description: scheme.Description}
+		switch scheme.In {
+		case "header":
+			request.Header = append(request.Header, v)
+		case "query":
+			request.URL.Query = append(request.URL.Query, v)
+		}
+	}
+	if len(request.URL.Query) > 0 {
+		var query []string
+		for _, q := range request.URL.Query {
+			query = append(query, q.Key+"="+q.Value)
+		}
+		request.URL.Raw += "?" + strings.Join(query, "&")
+	}
+
+	name := op.Summary
+	if len(name) == 0 {
+		name = op.OperationID
+	}
+	return postmanItem{Name: name, Request: request}, nil
+}
+
+// securitySchemesOf returns the apiKey schemes op requires, the first
+// alternative of its security requirements.
+func securitySchemesOf(s *swaggerObject, op *swaggerOperationObject) []swaggerSecuritySchemeObject {
+	if op.Security == nil || len(*op.Security) == 0 {
+		return nil
+	}
+
+	var ret []swaggerSecuritySchemeObject
+	for _, name := range sortedSchemeNames((*op.Security)[0]) {
+		if scheme, ok := s.SecurityDefinitions[name]; ok && scheme.Type == "apiKey" {
+			ret = append(ret, scheme)
+		}
+	}
+	return ret
+}
+
+func sortedSchemeNames(requirement swaggerSecurityRequirementObject) []string {
+	names := make([]string, 0, len(requirement))
+	for name := range requirement {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,15 @@
+package generate_test
+
+import (
+	"testing"
+
+	"github.com/dyntrait/goctl-swagger/generate"
+)
+
+func TestPostmanGolden(t *testing.T) {
+	got, err := generate.RenderPostman(loadAPI(t, "shop.api"), generate.Options{Host: "localhost:8888", Config: loadConfig(t, "shop.yaml")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "shop.postman.json", got)
+}
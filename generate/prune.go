@@ -0,0 +1,180 @@
+package generate
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/zeromicro/go-zero/tools/goctl/api/spec"
+)
+
+// pruneDefinitions reports the definitions that no route refers to, directly
+// or through other definitions, e.g. types left over in the .api files. With
+// prune they are removed, and so are the definitions without any property
+// such as request types made of path, header and form members only.
+// Embedded types are not referenced in the document once flattened, they are
+// not reported.
+func pruneDefinitions(s *swaggerObject, roots refMap, embedded map[string]bool, prune bool) {
+	reachable := reachableDefinitions(s.Definitions, roots)
+
+	var orphans []string
+	for name := range s.Definitions {
+		if !reachable[name] && !embedded[name] {
+			orphans = append(orphans, name)
+		}
+	}
+	sort.Strings(orphans)
+	for _, name := range orphans {
+		warn(warning{Type: name, Reason: "type is not used by any route"})
+	}
+
+	if !prune {
+		return
+	}
+
+	empty := map[string]bool{}
+	for name, def := range s.Definitions {
+		if isEmptyDefinition(def) {
+			empty[name] = true
+		}
+	}
+
+	for name := range s.Definitions {
+		if !reachable[name] || empty[name] {
+			debugf("prune definition %s", name)
+			delete(s.Definitions, name)
+		}
+	}
+
+	for name, def := range s.Definitions {
+		s.Definitions[name] = stripEmptyRefs(def, empty)
+	}
+	for _, item := range s.Paths {
+		for _, op := range []*swaggerOperationObject{item.Get, item.Delete, item.Post, item.Put, item.Patch} {
+			if op == nil {
+				continue
+			}
+
+			// a body without any property is left out
+			params := op.Parameters[:0]
+			for _, param := range op.Parameters {
+				if param.Schema != nil && empty[strings.TrimPrefix(param.Schema.Ref, swaggerDefinitionRef)] {
+					continue
+				}
+				params = append(params, param)
+			}
+			op.Parameters = params
+
+			for code, resp := range op.Responses {
+				if resp.Schema != nil && empty[strings.TrimPrefix(resp.Schema.Ref, swaggerDefinitionRef)] {
+					resp.Schema = nil
+					op.Responses[code] = resp
+				}
+			}
+		}
+	}
+}
+
+// reachableDefinitions returns the definitions referred to by roots and,
+// transitively, by the definitions they refer to.
+func reachableDefinitions(d swaggerDefinitionsObject, roots refMap) map[string]bool {
+	reachable := map[string]bool{}
+	var visit func(ref string)
+	visit = func(ref string) {
+		name := strings.TrimPrefix(ref, swaggerDefinitionRef)
+		def, ok := d[name]
+		if !ok || reachable[name] {
+			return
+		}
+		reachable[name] = true
+		walkRefs(def, visit)
+	}
+
+	for ref := range roots {
+		visit(ref)
+	}
+	return reachable
+}
+
+// walkRefs calls visit with every $ref in schema.
+func walkRefs(schema swaggerSchemaObject, visit func(ref string)) {
+	if len(schema.Ref) > 0 {
+		visit(schema.Ref)
+	}
+	if schema.Items != nil {
+		walkRefs(swaggerSchemaObject(*schema.Items), visit)
+	}
+	if schema.AdditionalProperties != nil {
+		walkRefs(*schema.AdditionalProperties, visit)
+	}
+	for _, sub := range schema.AllOf {
+		walkRefs(sub, visit)
+	}
+	if schema.Properties != nil {
+		for _, kv := range *schema.Properties {
+			if prop, ok := kv.Value.(swaggerSchemaObject); ok {
+				walkRefs(prop, visit)
+			}
+		}
+	}
+}
+
+// isEmptyDefinition reports whether def is an object without any property.
+func isEmptyDefinition(def swaggerSchemaObject) bool {
+	return def.Type == "object" && (def.Properties == nil || len(*def.Properties) == 0) &&
+		def.AdditionalProperties == nil && len(def.AllOf) == 0
+}
+
+// stripEmptyRefs replaces the references to empty definitions in schema with
+// free-form objects, and drops them from allOf.
+func stripEmptyRefs(schema swaggerSchemaObject, empty map[string]bool) swaggerSchemaObject {
+	if len(schema.Ref) > 0 && empty[strings.TrimPrefix(schema.Ref, swaggerDefinitionRef)] {
+		schema.Ref = ""
+		schema.Type = "object"
+	}
+	if schema.Items != nil {
+		items := stripEmptyRefs(swaggerSchemaObject(*schema.Items), empty)
+		schema.Items = (*swaggerItemsObject)(&items)
+	}
+	if schema.AdditionalProperties != nil {
+		additional := stripEmptyRefs(*schema.AdditionalProperties, empty)
+		schema.AdditionalProperties = &additional
+	}
+	if len(schema.AllOf) > 0 {
+		allOf := make([]swaggerSchemaObject, 0, len(schema.AllOf))
+		for _, sub := range schema.AllOf {
+			if empty[strings.TrimPrefix(sub.Ref, swaggerDefinitionRef)] {
+				continue
+			}
+			allOf = append(allOf, stripEmptyRefs(sub, empty))
+		}
+		schema.AllOf = allOf
+	}
+	if schema.Properties != nil {
+		props := make(swaggerSchemaObjectProperties, 0, len(*schema.Properties))
+		for _, kv := range *schema.Properties {
+			if prop, ok := kv.Value.(swaggerSchemaObject); ok {
+				kv.Value = stripEmptyRefs(prop, empty)
+			}
+			props = append(props, kv)
+		}
+		schema.Properties = &props
+	}
+	return schema
+}
+
+// embeddedTypes returns the structs embedded in other structs.
+func embeddedTypes(api *spec.ApiSpec) map[string]bool {
+	types := StructsByName(api.Types)
+	ret := map[string]bool{}
+	for _, s := range types {
+		for _, member := range s.Members {
+			if !member.IsInline {
+				continue
+			}
+			if embedded, ok := ResolveStruct(member.Type, types); ok {
+				ret[embedded.Name()] = true
+			}
+		}
+	}
+	return ret
+}
@@ -0,0 +1,170 @@
+package generate
+
+import (
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/zeromicro/go-zero/tools/goctl/api/spec"
+)
+
+const atRespDoc = "@respdoc-"
+
+var (
+	// // @respdoc-404 (NotFoundReply) resource not found
+	respDocCommentPattern = regexp.MustCompile(`^` + atRespDoc + `(\d{3})\s*(?:\((\w+)\))?\s*(.*)$`)
+	// @doc(respdoc_404: "(NotFoundReply) resource not found"), the api syntax
+	// does not allow '-' in @doc keys
+	respDocPropertyPattern = regexp.MustCompile(`^respdoc[-_](\d{3})$`)
+	respDocValuePattern    = regexp.MustCompile(`^\s*(?:\((\w+)\))?\s*(.*)$`)
+)
+
+// respDoc is an additional response declared on a route with @respdoc-<code>.
+type respDoc struct {
+	Code        string
+	Type        string
+	Description string
+}
+
+// parseRespDocs collects the @respdoc annotations of route from its @doc
+// properties and its comments, ordered by status code.
+func parseRespDocs(route spec.Route) []respDoc {
+	docs := map[string]respDoc{}
+
+	for key, value := range route.AtDoc.Properties {
+		match := respDocPropertyPattern.FindStringSubmatch(key)
+		if match == nil {
+			continue
+		}
+
+		if unquoted, err := strconv.Unquote(value); err == nil {
+			value = unquoted
+		}
+		parts := respDocValuePattern.FindStringSubmatch(value)
+		docs[match[1]] = respDoc{Code: match[1], Type: parts[1], Description: strings.TrimSpace(parts[2])}
+	}
+
+	var comments []string
+	comments = append(comments, route.HandlerDoc...)
+	comments = append(comments, route.HandlerComment...)
+	comments = append(comments, route.Doc...)
+	comments = append(comments, route.Docs...)
+	comments = append(comments, route.Comment...)
+	for _, comment := range comments {
+		for _, line := range strings.Split(comment, "\n") {
+			line = strings.TrimSpace(line)
+			line = strings.TrimPrefix(line, "//")
+			line = strings.TrimPrefix(line, "/*")
+			line = strings.TrimSuffix(line, "*/")
+			line = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "*"))
+
+			match := respDocCommentPattern.FindStringSubmatch(line)
+			if match == nil {
+				continue
+			}
+			docs[match[1]] = respDoc{Code: match[1], Type: match[2], Description: strings.TrimSpace(match[3])}
+		}
+	}
+
+	ret := make([]respDoc, 0, len(docs))
+	for _, doc := range docs {
+		if len(doc.Description) == 0 {
+			code, _ := strconv.Atoi(doc.Code)
+			doc.Description = http.StatusText(code)
+		}
+		ret = append(ret, doc)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Code < ret[j].Code
+	})
+
+	return ret
+}
+
+// isErrorCode reports whether code is a 4xx or 5xx status code.
+func isErrorCode(code string) bool {
+	return strings.HasPrefix(code, "4") || strings.HasPrefix(code, "5")
+}
+
+// renderRespDocs adds the responses declared with @respdoc to responses, and
+// the default error responses of the configuration to every route. A response
+// naming an undeclared type is rendered without a schema.
+func renderRespDocs(responses swaggerResponsesObject, route spec.Route, path string, types map[string]spec.DefineStruct, cfg *Config, wrap func(respType string) string) {
+	var errorSchema *swaggerSchemaObject
+	if cfg.ErrorResponse != nil {
+		switch {
+		case len(cfg.ErrorResponse.Type) > 0:
+			errorSchema = &swaggerSchemaObject{schemaCore: schemaCore{Ref: swaggerDefinitionRef + cfg.ErrorResponse.Type}}
+		case cfg.ErrorResponse.Schema != nil:
+			errorSchema = cfg.ErrorResponse.Schema
+		}
+
+		for _, code := range cfg.ErrorResponse.Codes {
+			responses[strconv.Itoa(code)] = swaggerResponseObject{
+				Description: http.StatusText(code),
+				Schema:      errorSchema,
+			}
+		}
+	}
+
+	for _, doc := range parseRespDocs(route) {
+		resp := swaggerResponseObject{Description: doc.Description}
+		if _, ok := types[doc.Type]; len(doc.Type) > 0 && !ok {
+			warn(warning{
+				Route:  strings.ToUpper(route.Method) + " " + path,
+				Type:   doc.Type,
+				Reason: "response " + doc.Code + " refers to an undeclared type, schema omitted",
+			})
+			responses[doc.Code] = resp
+			continue
+		}
+
+		switch {
+		case len(doc.Type) > 0 && strings.HasPrefix(doc.Code, "2"):
+			resp.Schema = &swaggerSchemaObject{schemaCore: schemaCore{Ref: wrap(doc.Type)}}
+		case len(doc.Type) > 0:
+			resp.Schema = &swaggerSchemaObject{schemaCore: schemaCore{Ref: swaggerDefinitionRef + doc.Type}}
+		case isErrorCode(doc.Code):
+			resp.Schema = errorSchema
+		}
+		responses[doc.Code] = resp
+	}
+}
+
+// dropDanglingResponses removes the schema of responses referring to types
+// that are not declared, e.g. a typo in the errorResponse configuration.
+// The operations are visited in route order and the codes in numeric order
+// so the warnings are stable.
+func dropDanglingResponses(s *swaggerObject) {
+	for _, ref := range s.operations {
+		op := s.Paths[ref.Path].operation(ref.Method)
+
+		codes := make([]string, 0, len(op.Responses))
+		for code := range op.Responses {
+			codes = append(codes, code)
+		}
+		sortResponseCodes(codes)
+
+		for _, code := range codes {
+			resp := op.Responses[code]
+			if resp.Schema == nil {
+				continue
+			}
+			name := strings.TrimPrefix(resp.Schema.Ref, swaggerDefinitionRef)
+			if len(name) == 0 {
+				continue
+			}
+			if _, ok := s.Definitions[name]; !ok {
+				warn(warning{
+					Route:  strings.ToUpper(ref.Method) + " " + ref.Path,
+					Type:   name,
+					Reason: "response " + code + " refers to an undeclared type, schema omitted",
+				})
+				resp.Schema = nil
+				op.Responses[code] = resp
+			}
+		}
+	}
+}
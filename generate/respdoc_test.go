@@ -0,0 +1,55 @@
+package generate
+
+import (
+	"testing"
+)
+
+func TestDanglingResponsesOrder(t *testing.T) {
+	in := loadAPI(t, "respdoc.api")
+
+	want := `level=warn route="GET /users/{id}" type=NotFoundReply reason="response 404 refers to an undeclared type, schema omitted"
+level=warn route="GET /users/{id}" type=ConflictReply reason="response 409 refers to an undeclared type, schema omitted"
+level=warn route="DELETE /users/{id}" type=ForbiddenReply reason="response 403 refers to an undeclared type, schema omitted"
+level=warn route="POST /users" type=AcceptedReply reason="response 202 refers to an undeclared type, schema omitted"
+level=warn route="POST /users" type=BadRequestReply reason="response 400 refers to an undeclared type, schema omitted"
+`
+	for i := 0; i < 10; i++ {
+		got := captureLog(t, func() {
+			if _, err := Render(in, Options{}); err != nil {
+				t.Fatal(err)
+			}
+		})
+		if got != want {
+			t.Fatalf("run %d warnings:\n%s\nwant:\n%s", i, got, want)
+		}
+	}
+}
+
+func TestDanglingResponsesOmitted(t *testing.T) {
+	cfg := &Config{
+		Envelope:      &EnvelopeConfig{},
+		ErrorResponse: &ErrorResponseConfig{Type: "CodeError", Codes: []int{500}},
+	}
+	s, err := build(loadAPI(t, "respdoc.api"), Options{Config: cfg})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	op := s.Paths["/users/{id}"].Get
+	for _, code := range []string{"404", "409", "500"} {
+		resp, ok := op.Responses[code]
+		if !ok {
+			t.Errorf("response %s is missing", code)
+			continue
+		}
+		if resp.Schema != nil {
+			t.Errorf("response %s schema = %s, want none", code, resp.Schema.Ref)
+		}
+	}
+	if resp := s.Paths["/users"].Post.Responses["202"]; resp.Schema != nil {
+		t.Errorf("response 202 schema = %s, want none", resp.Schema.Ref)
+	}
+	if _, ok := s.Definitions["AcceptedReplyEnvelope"]; ok {
+		t.Error("envelope of an undeclared type is rendered")
+	}
+}
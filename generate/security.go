@@ -0,0 +1,110 @@
+package generate
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/zeromicro/go-zero/tools/goctl/api/spec"
+)
+
+const (
+	defaultSecurityScheme = "apiKey"
+	jwtAnnotation         = "jwt"
+)
+
+// defaultSecuritySchemes are used when the configuration declares none.
+var defaultSecuritySchemes = map[string]swaggerSecuritySchemeObject{
+	defaultSecurityScheme: {
+		Type:        "apiKey",
+		Description: "Enter JWT Bearer token **_only_**",
+		Name:        "Authorization",
+		In:          "header",
+	},
+}
+
+var securitySchemeTypes = []string{"apiKey", "basic", "oauth2"}
+
+// SecurityRequirement names a declared security scheme and the scopes it requires.
+type SecurityRequirement struct {
+	Scheme string   `json:"scheme"`
+	Scopes []string `json:"scopes,omitempty"`
+}
+
+// securitySchemes returns the configured schemes, or the default apiKey scheme.
+func (c *Config) securitySchemes() map[string]swaggerSecuritySchemeObject {
+	if len(c.SecuritySchemes) == 0 {
+		return defaultSecuritySchemes
+	}
+	return c.SecuritySchemes
+}
+
+// validateSecurity checks the scheme types and that every jwt mapping refers
+// to a declared scheme.
+func (c *Config) validateSecurity() error {
+	for name, scheme := range c.SecuritySchemes {
+		if !contains(securitySchemeTypes, scheme.Type) {
+			return fmt.Errorf("security scheme %s: unsupported type %q, expected one of %s",
+				name, scheme.Type, strings.Join(securitySchemeTypes, ", "))
+		}
+	}
+
+	schemes := c.securitySchemes()
+	for jwt, requirements := range c.JWT {
+		for _, req := range requirements {
+			if _, ok := schemes[req.Scheme]; !ok {
+				return fmt.Errorf("jwt %s: undeclared security scheme %q", jwt, req.Scheme)
+			}
+		}
+	}
+
+	return nil
+}
+
+// renderSecurityDefinitions declares the schemes in the document.
+func renderSecurityDefinitions(cfg *Config) swaggerSecurityDefinitionsObject {
+	d := swaggerSecurityDefinitionsObject{}
+	for name, scheme := range cfg.securitySchemes() {
+		d[name] = scheme
+	}
+	return d
+}
+
+// securityOfGroup returns the security requirements of the routes in group,
+// nil for public groups without @server(jwt: X).
+//
+// The jwt value X is looked up in the jwt mapping of the configuration, then
+// as a scheme name, and the default apiKey scheme is used when the
+// configuration declares no schemes at all.
+func securityOfGroup(cfg *Config, group spec.Group) *[]swaggerSecurityRequirementObject {
+	jwt := strings.Trim(group.GetAnnotation(jwtAnnotation), `"`)
+	if len(jwt) == 0 {
+		return nil
+	}
+
+	var requirements []SecurityRequirement
+	schemes := cfg.securitySchemes()
+	if mapped, ok := cfg.JWT[jwt]; ok {
+		requirements = mapped
+	} else if _, ok := schemes[jwt]; ok {
+		requirements = []SecurityRequirement{{Scheme: jwt}}
+	} else if len(cfg.SecuritySchemes) == 0 {
+		requirements = []SecurityRequirement{{Scheme: defaultSecurityScheme}}
+	} else {
+		warn(warning{
+			Route:  group.GetAnnotation(spec.RoutePrefixKey),
+			Type:   jwt,
+			Reason: "jwt is not mapped to a security scheme, routes rendered without security",
+		})
+		return nil
+	}
+
+	// each requirement is an alternative, any of them grants access
+	security := make([]swaggerSecurityRequirementObject, 0, len(requirements))
+	for _, req := range requirements {
+		scopes := append([]string{}, req.Scopes...)
+		sort.Strings(scopes)
+		security = append(security, swaggerSecurityRequirementObject{req.Scheme: scopes})
+	}
+	return &security
+}
@@ -0,0 +1,77 @@
+package generate
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"gopkg.in/yaml.v2"
+)
+
+// jsonToYAML re-encodes a JSON document as YAML. Decoding into yaml.MapSlice
+// keeps the key order of the JSON input, so the YAML output is as stable as
+// the JSON one, including the property order of swaggerSchemaObjectProperties.
+func jsonToYAML(data []byte) ([]byte, error) {
+	var doc yaml.MapSlice
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	return yaml.Marshal(doc)
+}
+
+// yamlToJSON converts a YAML (or JSON) document to JSON without losing the
+// order of mapping keys, which matters for schemas in the configuration.
+func yamlToJSON(data []byte) ([]byte, error) {
+	var doc yaml.MapSlice
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := writeJSONValue(&buf, doc); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func writeJSONValue(buf *bytes.Buffer, v interface{}) error {
+	switch val := v.(type) {
+	case yaml.MapSlice:
+		buf.WriteString("{")
+		for i, item := range val {
+			if i != 0 {
+				buf.WriteString(",")
+			}
+			key, err := json.Marshal(fmt.Sprint(item.Key))
+			if err != nil {
+				return err
+			}
+			buf.Write(key)
+			buf.WriteString(":")
+			if err := writeJSONValue(buf, item.Value); err != nil {
+				return err
+			}
+		}
+		buf.WriteString("}")
+	case []interface{}:
+		buf.WriteString("[")
+		for i, item := range val {
+			if i != 0 {
+				buf.WriteString(",")
+			}
+			if err := writeJSONValue(buf, item); err != nil {
+				return err
+			}
+		}
+		buf.WriteString("]")
+	default:
+		b, err := json.Marshal(val)
+		if err != nil {
+			return err
+		}
+		buf.Write(b)
+	}
+
+	return nil
+}
@@ -0,0 +1,139 @@
+// Package lint checks the documentation quality of .api files, such as
+// routes without a summary or members without a comment.
+package lint
+
+import (
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/dyntrait/goctl-swagger/generate"
+	"github.com/zeromicro/go-zero/tools/goctl/api/spec"
+	"gopkg.in/yaml.v2"
+)
+
+// Severities of the rules, SeverityOff disables a rule.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+	SeverityInfo    = "info"
+	SeverityOff     = "off"
+)
+
+var severities = []string{SeverityError, SeverityWarning, SeverityInfo, SeverityOff}
+
+// ignorePattern matches the inline suppression comments, such as
+// // lint:ignore missing-comment,json-camel-case
+// on a member, or above a type or a @handler. Without rules all of them are ignored.
+var ignorePattern = regexp.MustCompile(`lint:ignore(?:\s+([\w,-]+))?`)
+
+// Finding is a rule violation.
+type Finding struct {
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	// Location is the route, type or member, such as type LoginReq member Password.
+	Location string `json:"location"`
+	Message  string `json:"message"`
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("%s: %s: %s (%s)", f.Severity, f.Location, f.Message, f.Rule)
+}
+
+// Config overrides the default severities of the rules.
+type Config struct {
+	Rules map[string]string `yaml:"rules" json:"rules"`
+}
+
+// LoadConfig reads the YAML or JSON configuration at path.
+func LoadConfig(path string) (*Config, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, &generate.InputError{Source: path, Err: err}
+	}
+
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, &generate.InputError{Source: path, Err: err}
+	}
+	for name, severity := range cfg.Rules {
+		if _, ok := ruleByName(name); !ok {
+			return nil, &generate.InputError{Source: path, Err: fmt.Errorf("unknown rule %q", name)}
+		}
+		switch severity {
+		case SeverityError, SeverityWarning, SeverityInfo, SeverityOff:
+		default:
+			return nil, &generate.OptionError{Option: "severity of " + name, Value: severity, Expected: severities}
+		}
+	}
+	return &cfg, nil
+}
+
+// Error is returned by Check when there are findings of severity error.
+type Error struct {
+	Errors int
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%d lint error(s)", e.Errors)
+}
+
+// Run checks api with the rules enabled in cfg, cfg may be nil.
+func Run(api *spec.ApiSpec, cfg *Config) []Finding {
+	var findings []Finding
+	for _, rule := range rules {
+		severity := rule.Severity
+		if cfg != nil && len(cfg.Rules[rule.Name]) > 0 {
+			severity = cfg.Rules[rule.Name]
+		}
+		if severity == SeverityOff {
+			continue
+		}
+
+		rule.Check(api, func(location, message string, comments ...string) {
+			if ignored(rule.Name, comments) {
+				return
+			}
+			findings = append(findings, Finding{Rule: rule.Name, Severity: severity, Location: location, Message: message})
+		})
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findings[i].Location < findings[j].Location
+	})
+	return findings
+}
+
+// Check runs the rules and returns an *Error if any finding is an error.
+func Check(api *spec.ApiSpec, cfg *Config) ([]Finding, error) {
+	findings := Run(api, cfg)
+	errors := 0
+	for _, f := range findings {
+		if f.Severity == SeverityError {
+			errors++
+		}
+	}
+	if errors > 0 {
+		return findings, &Error{Errors: errors}
+	}
+	return findings, nil
+}
+
+// ignored reports whether one of comments suppresses rule.
+func ignored(rule string, comments []string) bool {
+	for _, comment := range comments {
+		for _, match := range ignorePattern.FindAllStringSubmatch(comment, -1) {
+			if len(match[1]) == 0 {
+				return true
+			}
+			for _, name := range strings.Split(match[1], ",") {
+				if name == rule {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
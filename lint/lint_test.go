@@ -0,0 +1,102 @@
+package lint
+
+import (
+	"errors"
+	"io"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/dyntrait/goctl-swagger/generate"
+	"github.com/zeromicro/go-zero/tools/goctl/api/parser"
+	"github.com/zeromicro/go-zero/tools/goctl/api/spec"
+)
+
+func TestMain(m *testing.M) {
+	// cyclic.api makes the generator warn
+	generate.SetLogOutput(io.Discard)
+	os.Exit(m.Run())
+}
+
+func parse(t *testing.T, name string) *spec.ApiSpec {
+	t.Helper()
+	api, err := parser.Parse("../tests/" + name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return api
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		file     string
+		errors   int
+		findings []string
+	}{
+		{"lint.api", 1, []string{
+			"error: route GET /members/:user_id: member Role of MemberReq is sent in the json body of a GET request, use form instead (get-json-body)",
+			"warning: route GET /members/:user_id: path parameter user_id is not lowerCamelCase (path-param-naming)",
+			"warning: route GET /members/:user_id: path parameter user_id has no path member in MemberReq (path-param-naming)",
+			"warning: route GET /members/:user_id: path member orgId does not appear in the path (path-param-naming)",
+			"warning: route GET /members/:user_id: path member teamId does not appear in the path (path-param-naming)",
+			"warning: route GET /members/:user_id: path member userId does not appear in the path (path-param-naming)",
+			"warning: route POST /rename: route has no @doc summary (missing-summary)",
+			"warning: type Req: type name is too generic, name what it holds such as LoginReq (generic-type-name)",
+			`warning: type Req member Name: json name "user_name" is not lowerCamelCase (json-camel-case)`,
+		}},
+		{"cyclic.api", 0, []string{
+			"info: type Node member ID: member has no comment (missing-comment)",
+			"info: type Node member Name: member has no comment (missing-comment)",
+			"info: type Tree member Depth: member has no comment (missing-comment)",
+		}},
+	}
+	for _, tt := range tests {
+		api := parse(t, tt.file)
+		findings, err := Check(api, nil)
+		var lintErr *Error
+		switch {
+		case tt.errors == 0 && err != nil:
+			t.Errorf("%s: %v", tt.file, err)
+		case tt.errors > 0 && (!errors.As(err, &lintErr) || lintErr.Errors != tt.errors):
+			t.Errorf("%s: error = %v, want %d lint error(s)", tt.file, err, tt.errors)
+		}
+
+		var got []string
+		for _, f := range findings {
+			got = append(got, f.String())
+		}
+		if !reflect.DeepEqual(got, tt.findings) {
+			t.Errorf("%s findings:\n%q\nwant:\n%q", tt.file, got, tt.findings)
+		}
+
+		// the report is the same on every run
+		for i := 0; i < 10; i++ {
+			if again := Run(api, nil); !reflect.DeepEqual(again, findings) {
+				t.Fatalf("%s run %d: %v, want %v", tt.file, i, again, findings)
+			}
+		}
+	}
+}
+
+func TestRunConfig(t *testing.T) {
+	api := parse(t, "lint.api")
+	cfg := &Config{Rules: map[string]string{
+		"get-json-body":     SeverityWarning,
+		"path-param-naming": SeverityOff,
+		// IgnoredReq suppresses it with lint:ignore, the other members have comments
+		"missing-comment": SeverityError,
+	}}
+	findings, err := Check(api, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var rules []string
+	for _, f := range findings {
+		rules = append(rules, f.Severity+" "+f.Rule)
+	}
+	want := []string{"warning get-json-body", "warning missing-summary", "warning generic-type-name", "warning json-camel-case"}
+	if !reflect.DeepEqual(rules, want) {
+		t.Errorf("findings = %q, want %q", rules, want)
+	}
+}
@@ -0,0 +1,252 @@
+package lint
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/dyntrait/goctl-swagger/generate"
+	"github.com/zeromicro/go-zero/tools/goctl/api/spec"
+)
+
+// report records a finding at location unless one of comments suppresses it.
+type report func(location, message string, comments ...string)
+
+// Rule is a check over the parsed .api file.
+type Rule struct {
+	Name string
+	// Severity is the default severity.
+	Severity    string
+	Description string
+	Check       func(api *spec.ApiSpec, report report)
+}
+
+var rules = []Rule{
+	{
+		Name:        "missing-summary",
+		Severity:    SeverityWarning,
+		Description: "routes have a @doc summary",
+		Check:       checkSummary,
+	},
+	{
+		Name:        "missing-comment",
+		Severity:    SeverityInfo,
+		Description: "members of the request and response types have a comment",
+		Check:       checkMemberComments,
+	},
+	{
+		Name:        "json-camel-case",
+		Severity:    SeverityWarning,
+		Description: "json names are lowerCamelCase",
+		Check:       checkJSONNames,
+	},
+	{
+		Name:        "generic-type-name",
+		Severity:    SeverityWarning,
+		Description: "type names say what they hold, not only Req or Reply",
+		Check:       checkTypeNames,
+	},
+	{
+		Name:        "get-json-body",
+		Severity:    SeverityError,
+		Description: "GET routes do not have json body members",
+		Check:       checkGetBody,
+	},
+	{
+		Name:        "path-param-naming",
+		Severity:    SeverityWarning,
+		Description: "path parameters are lowerCamelCase and match a path member of the request type",
+		Check:       checkPathParams,
+	},
+}
+
+// Rules returns the available rules.
+func Rules() []Rule {
+	return append([]Rule(nil), rules...)
+}
+
+func ruleByName(name string) (Rule, bool) {
+	for _, rule := range rules {
+		if rule.Name == name {
+			return rule, true
+		}
+	}
+	return Rule{}, false
+}
+
+var (
+	lowerCamelPattern = regexp.MustCompile(`^[a-z][a-zA-Z0-9]*$`)
+	genericTypeNames  = []string{"Req", "Request", "Resp", "Response", "Reply", "Data", "Info", "Result"}
+)
+
+func checkSummary(api *spec.ApiSpec, report report) {
+	for _, group := range api.Service.Groups {
+		for _, route := range group.Routes {
+			if len(strings.Trim(route.JoinedDoc(), `"`)) > 0 {
+				continue
+			}
+			report(routeLocation(group, route), "route has no @doc summary", routeComments(route)...)
+		}
+	}
+}
+
+func checkMemberComments(api *spec.ApiSpec, report report) {
+	for _, s := range structs(api) {
+		for _, member := range s.Members {
+			if member.IsInline || len(memberTag(member)) == 0 {
+				continue
+			}
+			if len(strings.TrimSpace(strings.TrimPrefix(member.Comment, "//"))) > 0 || len(member.Docs) > 0 {
+				continue
+			}
+			report(memberLocation(s, member), "member has no comment", memberComments(s, member)...)
+		}
+	}
+}
+
+func checkJSONNames(api *spec.ApiSpec, report report) {
+	for _, s := range structs(api) {
+		for _, member := range s.Members {
+			for _, tag := range member.Tags() {
+				if tag.Key != "json" || tag.Name == "-" || lowerCamelPattern.MatchString(tag.Name) {
+					continue
+				}
+				report(memberLocation(s, member), fmt.Sprintf("json name %q is not lowerCamelCase", tag.Name), memberComments(s, member)...)
+			}
+		}
+	}
+}
+
+func checkTypeNames(api *spec.ApiSpec, report report) {
+	for _, s := range structs(api) {
+		for _, name := range genericTypeNames {
+			if s.Name() == name {
+				report("type "+s.Name(), "type name is too generic, name what it holds such as LoginReq", s.Docs...)
+				break
+			}
+		}
+	}
+}
+
+func checkGetBody(api *spec.ApiSpec, report report) {
+	types := generate.StructsByName(api.Types)
+	for _, group := range api.Service.Groups {
+		for _, route := range group.Routes {
+			if !strings.EqualFold(route.Method, "get") {
+				continue
+			}
+			s, ok := generate.ResolveStruct(route.RequestType, types)
+			if !ok {
+				continue
+			}
+			for _, member := range bodyMembers(s, types) {
+				report(routeLocation(group, route),
+					fmt.Sprintf("member %s of %s is sent in the json body of a GET request, use form instead", member.Name, s.Name()),
+					append(routeComments(route), memberComments(s, member)...)...)
+			}
+		}
+	}
+}
+
+func checkPathParams(api *spec.ApiSpec, report report) {
+	types := generate.StructsByName(api.Types)
+	for _, group := range api.Service.Groups {
+		for _, route := range group.Routes {
+			location := routeLocation(group, route)
+			members := map[string]bool{}
+			if s, ok := generate.ResolveStruct(route.RequestType, types); ok {
+				for _, name := range pathMembers(s, types) {
+					members[name] = true
+				}
+			}
+
+			for _, segment := range strings.Split(route.Path, "/") {
+				if !strings.HasPrefix(segment, ":") {
+					continue
+				}
+				name := segment[1:]
+				if !lowerCamelPattern.MatchString(name) {
+					report(location, fmt.Sprintf("path parameter %s is not lowerCamelCase", name), routeComments(route)...)
+				}
+				if route.RequestType != nil && !members[name] {
+					report(location, fmt.Sprintf("path parameter %s has no path member in %s", name, route.RequestType.Name()), routeComments(route)...)
+				}
+				delete(members, name)
+			}
+			var unused []string
+			for name := range members {
+				unused = append(unused, name)
+			}
+			sort.Strings(unused)
+			for _, name := range unused {
+				report(location, fmt.Sprintf("path member %s does not appear in the path", name), routeComments(route)...)
+			}
+		}
+	}
+}
+
+func routeLocation(group spec.Group, route spec.Route) string {
+	return "route " + strings.ToUpper(route.Method) + " " + group.GetAnnotation(spec.RoutePrefixKey) + route.Path
+}
+
+func memberLocation(s spec.DefineStruct, member spec.Member) string {
+	return "type " + s.Name() + " member " + member.Name
+}
+
+func routeComments(route spec.Route) []string {
+	var ret []string
+	ret = append(ret, route.HandlerDoc...)
+	ret = append(ret, route.HandlerComment...)
+	ret = append(ret, route.Doc...)
+	ret = append(ret, route.Comment...)
+	return ret
+}
+
+// memberComments are the comments suppressing rules on member, those of the
+// member itself and of its type.
+func memberComments(s spec.DefineStruct, member spec.Member) []string {
+	ret := append([]string{member.Comment}, member.Docs...)
+	return append(ret, s.Docs...)
+}
+
+func memberTag(member spec.Member) string {
+	for _, tag := range member.Tags() {
+		return tag.Key
+	}
+	return ""
+}
+
+func structs(api *spec.ApiSpec) []spec.DefineStruct {
+	var ret []spec.DefineStruct
+	for _, t := range api.Types {
+		if s, ok := t.(spec.DefineStruct); ok {
+			ret = append(ret, s)
+		}
+	}
+	return ret
+}
+
+// bodyMembers returns the json members of s, including those of embedded structs.
+func bodyMembers(s spec.DefineStruct, types map[string]spec.DefineStruct) []spec.Member {
+	var ret []spec.Member
+	for _, member := range generate.FlatMembers(s, types) {
+		if memberTag(member) == "json" {
+			ret = append(ret, member)
+		}
+	}
+	return ret
+}
+
+// pathMembers returns the path tag names of s, including those of embedded structs.
+func pathMembers(s spec.DefineStruct, types map[string]spec.DefineStruct) []string {
+	var ret []string
+	for _, member := range generate.FlatMembers(s, types) {
+		for _, tag := range member.Tags() {
+			if tag.Key == "path" {
+				ret = append(ret, tag.Name)
+			}
+		}
+	}
+	return ret
+}
@@ -18,6 +18,14 @@ var (
 			Usage:  "generates swagger.json",
 			Action: action.Generator,
 			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:  "api",
+					Usage: "parse this .api file instead of reading the goctl plugin payload from stdin",
+				},
+				&cli.StringFlag{
+					Name:  "dir",
+					Usage: "output directory when running with -api",
+				},
 				&cli.StringFlag{
 					Name:  "host",
 					Usage: "api request address",
@@ -30,11 +38,166 @@ var (
 					Name:  "filename",
 					Usage: "swagger save file name",
 				},
+				&cli.StringFlag{
+					Name:  "spec",
+					Usage: "output specification, swagger2, openapi3 or openapi3.1",
+					Value: "swagger2",
+				},
+				&cli.StringFlag{
+					Name:  "format",
+					Usage: "output format, json or yaml, inferred from the filename extension if omitted",
+				},
+				&cli.StringFlag{
+					Name:  "embed",
+					Usage: "how embedded structs are rendered, flatten or allof",
+					Value: "flatten",
+				},
+				&cli.StringFlag{
+					Name:  "operation-id",
+					Usage: "operationId strategy, handler, group-handler, method-path or a template such as {group}_{handler}",
+					Value: "handler",
+				},
+				&cli.BoolFlag{
+					Name:  "prune",
+					Usage: "remove definitions no route refers to and empty definitions",
+				},
+				&cli.StringFlag{
+					Name:  "config",
+					Usage: "configuration file in yaml or json",
+				},
+				&cli.StringFlag{
+					Name:  "log-level",
+					Usage: "lowest diagnostics level to print, debug, info, warn or error",
+					Value: "warn",
+				},
+				&cli.StringFlag{
+					Name:  "log-file",
+					Usage: "append diagnostics to this file instead of stderr",
+				},
+			},
+		},
+		{
+			Name:      "validate",
+			Usage:     "checks swagger 2.0 and openapi 3.x documents against the meta-schema and semantic rules",
+			ArgsUsage: "<file> [file...]",
+			Action:    action.Validator,
+		},
+		{
+			Name:      "diff",
+			Usage:     "reports the changes between two specs or .api files and fails on breaking changes",
+			ArgsUsage: "<old> <new>",
+			Action:    action.Differ,
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:  "format",
+					Usage: "report format, text or json",
+					Value: "text",
+				},
+				&cli.StringFlag{
+					Name:  "log-level",
+					Usage: "lowest diagnostics level to print while generating from .api files",
+					Value: "error",
+				},
+			},
+		},
+		{
+			Name:   "postman",
+			Usage:  "generates a postman v2.1 collection",
+			Action: action.PostmanGenerator,
+			Flags:  outputFlags("collection save file name, rest.postman_collection.json by default"),
+		},
+		{
+			Name:   "http",
+			Usage:  "generates a .http request file per group for the rest clients of jetbrains ides and vs code",
+			Action: action.HTTPGenerator,
+			Flags:  outputFlags("environment save file name, http-client.env.json by default"),
+		},
+		{
+			Name:   "markdown",
+			Usage:  "generates a markdown api reference",
+			Action: action.MarkdownGenerator,
+			Flags:  outputFlags("reference save file name, rest.md by default"),
+		},
+		{
+			Name:   "html",
+			Usage:  "generates a single offline html page documenting the api",
+			Action: action.HTMLGenerator,
+			Flags:  outputFlags("page save file name, rest.html by default"),
+		},
+		{
+			Name:      "lint",
+			Usage:     "checks the documentation quality of an .api file and fails on findings of severity error",
+			ArgsUsage: "<file.api>",
+			Action:    action.Linter,
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:  "config",
+					Usage: "rule severities in yaml or json, such as rules: {missing-comment: off}",
+				},
+				&cli.StringFlag{
+					Name:  "format",
+					Usage: "report format, text or json",
+					Value: "text",
+				},
 			},
 		},
 	}
 )
 
+// outputFlags are the flags of the commands generating other outputs than
+// the specification, filenameUsage tells what -filename names.
+func outputFlags(filenameUsage string) []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:  "api",
+			Usage: "parse this .api file instead of reading the goctl plugin payload from stdin",
+		},
+		&cli.StringFlag{
+			Name:  "dir",
+			Usage: "output directory when running with -api",
+		},
+		&cli.StringFlag{
+			Name:  "host",
+			Usage: "api request address",
+		},
+		&cli.StringFlag{
+			Name:  "basepath",
+			Usage: "url request prefix",
+		},
+		&cli.StringFlag{
+			Name:  "filename",
+			Usage: filenameUsage,
+		},
+		&cli.StringFlag{
+			Name:  "embed",
+			Usage: "how embedded structs are rendered, flatten or allof",
+			Value: "flatten",
+		},
+		&cli.BoolFlag{
+			Name:  "prune",
+			Usage: "remove definitions no route refers to and empty definitions",
+		},
+		&cli.StringFlag{
+			Name:  "operation-id",
+			Usage: "operationId strategy, handler, group-handler, method-path or a template such as {group}_{handler}",
+			Value: "handler",
+		},
+		&cli.StringFlag{
+			Name:  "config",
+			Usage: "configuration file in yaml or json",
+		},
+		&cli.StringFlag{
+			Name:  "log-level",
+			Usage: "lowest diagnostics level to print, debug, info, warn or error",
+			Value: "warn",
+		},
+		&cli.StringFlag{
+			Name:  "log-file",
+			Usage: "append diagnostics to this file instead of stderr",
+		},
+	}
+}
+
 func main() {
 	cli.VersionFlag = &cli.BoolFlag{
 		Name:    "print-version",
@@ -42,10 +205,11 @@ func main() {
 		Usage:   "print only the version",
 	}
 	app := cli.NewApp()
-	app.Usage = "a plugin of goctl to generate swagger.json"
+	app.Usage = "a plugin of goctl to generate swagger.json, or a standalone generator with -api"
 	app.Version = fmt.Sprintf("%s %s/%s", version, runtime.GOOS, runtime.GOARCH)
 	app.Commands = commands
 	if err := app.Run(os.Args); err != nil {
-		fmt.Printf("goctl-swagger1: %+v\n", err)
+		fmt.Fprintf(os.Stderr, "goctl-swagger: %+v\n", err)
+		os.Exit(action.ExitCode(err))
 	}
 }
@@ -0,0 +1,227 @@
+// Package restui serves a generated document and the page documenting it
+// from a go-zero rest server.
+//
+//	//go:embed rest.swagger.json
+//	var spec []byte
+//
+//	if err := restui.Register(server, spec); err != nil {
+//		log.Fatal(err)
+//	}
+//
+// registers GET /swagger for the page and GET /swagger-json for the document,
+// which goctl-swagger leaves out of the document by default. The default page
+// is the offline single-file page of the ui package, the one the html command
+// writes, it only renders the document. WithSwaggerUI and WithSwaggerUIURL
+// serve Swagger UI instead, which can also send requests:
+//
+//	//go:embed swagger-ui
+//	var dist embed.FS
+//
+//	assets, _ := fs.Sub(dist, "swagger-ui")
+//	err := restui.Register(server, spec, restui.WithSwaggerUI(assets))
+//
+// where swagger-ui holds the dist directory of the swagger-ui-dist npm package.
+// The package is a module of its own so the generator does not depend on the
+// go-zero server.
+package restui
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"errors"
+	"html/template"
+	"io/fs"
+	"net/http"
+	"strings"
+
+	"github.com/dyntrait/goctl-swagger/ui"
+	"github.com/zeromicro/go-zero/rest"
+)
+
+const (
+	// DefaultPath is the route of the page.
+	DefaultPath = "/swagger"
+	// DefaultSpecPath is the route of the document.
+	DefaultSpecPath = "/swagger-json"
+
+	// SwaggerUICDN serves the swagger-ui-dist release the page is written
+	// against, for WithSwaggerUIURL.
+	SwaggerUICDN = "https://unpkg.com/swagger-ui-dist@5.9.0"
+
+	defaultTitle = "API documentation"
+)
+
+// swaggerUIFiles are the files of swagger-ui-dist the Swagger UI page loads.
+var swaggerUIFiles = []struct {
+	name, contentType string
+}{
+	{"swagger-ui.css", "text/css; charset=utf-8"},
+	{"swagger-ui-bundle.js", "text/javascript; charset=utf-8"},
+	{"swagger-ui-standalone-preset.js", "text/javascript; charset=utf-8"},
+}
+
+//go:embed assets
+var assets embed.FS
+
+var swaggerUIPage = template.Must(template.ParseFS(assets, "assets/swagger-ui.html"))
+
+type config struct {
+	path     string
+	specPath string
+	title    string
+	// swaggerUI and swaggerUIURL select Swagger UI, served from the files of
+	// swaggerUI or loaded from swaggerUIURL.
+	swaggerUI    fs.FS
+	swaggerUIURL string
+}
+
+// Option customizes the routes.
+type Option func(*config)
+
+// WithPath sets the route of the page, DefaultPath by default.
+func WithPath(path string) Option {
+	return func(c *config) {
+		c.path = path
+	}
+}
+
+// WithSpecPath sets the route of the document, DefaultSpecPath by default.
+// An empty path only serves the page.
+func WithSpecPath(path string) Option {
+	return func(c *config) {
+		c.specPath = path
+	}
+}
+
+// WithTitle sets the title of the page, the title of the document by default.
+func WithTitle(title string) Option {
+	return func(c *config) {
+		c.title = title
+	}
+}
+
+// WithSwaggerUI serves Swagger UI instead of the default page, dist holds the
+// files of the dist directory of swagger-ui-dist, which are served under the
+// route of the page such as /swagger/swagger-ui-bundle.js.
+func WithSwaggerUI(dist fs.FS) Option {
+	return func(c *config) {
+		c.swaggerUI = dist
+	}
+}
+
+// WithSwaggerUIURL serves Swagger UI instead of the default page, loading
+// swagger-ui-dist from baseURL such as SwaggerUICDN.
+func WithSwaggerUIURL(baseURL string) Option {
+	return func(c *config) {
+		c.swaggerUIURL = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+// Register adds the routes serving spec and its page to server. Use Routes
+// and server.AddRoutes to pass route options such as rest.WithJwt.
+func Register(server *rest.Server, spec []byte, opts ...Option) error {
+	routes, err := Routes(spec, opts...)
+	if err != nil {
+		return err
+	}
+	server.AddRoutes(routes)
+	return nil
+}
+
+// Routes returns the routes serving spec, the JSON of a Swagger 2.0 or
+// OpenAPI 3.x document, and its page.
+func Routes(spec []byte, opts ...Option) ([]rest.Route, error) {
+	var doc struct {
+		Info struct {
+			Title string `json:"title"`
+		} `json:"info"`
+	}
+	if err := json.Unmarshal(spec, &doc); err != nil {
+		return nil, errors.New("restui: spec is not a json document: " + err.Error())
+	}
+
+	c := config{path: DefaultPath, specPath: DefaultSpecPath, title: doc.Info.Title}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	if len(c.title) == 0 {
+		c.title = defaultTitle
+	}
+
+	var page []byte
+	var err error
+	switch {
+	case c.swaggerUI != nil:
+		page, err = swaggerUI(c, strings.TrimSuffix(c.path, "/"), spec)
+	case len(c.swaggerUIURL) > 0:
+		page, err = swaggerUI(c, c.swaggerUIURL, spec)
+	default:
+		page, err = ui.Page(c.title, spec)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	routes := []rest.Route{{
+		Method:  http.MethodGet,
+		Path:    c.path,
+		Handler: content("text/html; charset=utf-8", page),
+	}}
+	if len(c.specPath) > 0 {
+		routes = append(routes, rest.Route{
+			Method:  http.MethodGet,
+			Path:    c.specPath,
+			Handler: content("application/json; charset=utf-8", spec),
+		})
+	}
+	if c.swaggerUI != nil {
+		files, err := swaggerUIRoutes(c.path, c.swaggerUI)
+		if err != nil {
+			return nil, err
+		}
+		routes = append(routes, files...)
+	}
+	return routes, nil
+}
+
+// swaggerUI renders the Swagger UI page loading swagger-ui-dist from
+// assetsURL, the document is inlined if it is not served.
+func swaggerUI(c config, assetsURL string, spec []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	err := swaggerUIPage.Execute(&buf, map[string]interface{}{
+		"Title":   c.title,
+		"Assets":  assetsURL,
+		"SpecURL": c.specPath,
+		"Spec":    template.JS(bytes.ReplaceAll(spec, []byte("<"), []byte(`\u003c`))),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// swaggerUIRoutes serves the files of swagger-ui-dist the page loads, go-zero
+// routes have no wildcards so each file gets a route.
+func swaggerUIRoutes(path string, dist fs.FS) ([]rest.Route, error) {
+	var routes []rest.Route
+	for _, file := range swaggerUIFiles {
+		body, err := fs.ReadFile(dist, file.name)
+		if err != nil {
+			return nil, errors.New("restui: swagger-ui-dist: " + err.Error())
+		}
+		routes = append(routes, rest.Route{
+			Method:  http.MethodGet,
+			Path:    strings.TrimSuffix(path, "/") + "/" + file.name,
+			Handler: content(file.contentType, body),
+		})
+	}
+	return routes, nil
+}
+
+func content(contentType string, body []byte) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		_, _ = w.Write(body)
+	}
+}
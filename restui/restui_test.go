@@ -0,0 +1,125 @@
+package restui
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+const spec = `{"swagger": "2.0", "info": {"title": "user-api </script>"}, "paths": {}}`
+
+func TestRoutes(t *testing.T) {
+	tests := []struct {
+		name  string
+		opts  []Option
+		paths []string
+		title string
+	}{
+		{"default", nil, []string{DefaultPath, DefaultSpecPath}, "user-api &lt;/script&gt;"},
+		{"custom", []Option{WithPath("/docs"), WithSpecPath("/docs.json"), WithTitle("User API")}, []string{"/docs", "/docs.json"}, "User API"},
+		{"page only", []Option{WithSpecPath("")}, []string{DefaultPath}, "user-api &lt;/script&gt;"},
+	}
+	for _, tt := range tests {
+		routes, err := Routes([]byte(spec), tt.opts...)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if len(routes) != len(tt.paths) {
+			t.Fatalf("%s: %d routes, want %d", tt.name, len(routes), len(tt.paths))
+		}
+
+		for i, route := range routes {
+			if route.Method != http.MethodGet || route.Path != tt.paths[i] {
+				t.Errorf("%s: route %s %s, want GET %s", tt.name, route.Method, route.Path, tt.paths[i])
+			}
+
+			w := httptest.NewRecorder()
+			route.Handler(w, httptest.NewRequest(http.MethodGet, route.Path, nil))
+			body := w.Body.String()
+			switch i {
+			case 0:
+				if !strings.HasPrefix(w.Header().Get("Content-Type"), "text/html") {
+					t.Errorf("%s: page content type %q", tt.name, w.Header().Get("Content-Type"))
+				}
+				if !strings.Contains(body, "<title>"+tt.title+"</title>") {
+					t.Errorf("%s: page has no title %q", tt.name, tt.title)
+				}
+				// the document must not close the script element it is inlined in
+				if strings.Count(body, "</script>") != strings.Count(body, "<script") {
+					t.Errorf("%s: unbalanced script elements", tt.name)
+				}
+			case 1:
+				if !strings.HasPrefix(w.Header().Get("Content-Type"), "application/json") || body != spec {
+					t.Errorf("%s: document %q %q", tt.name, w.Header().Get("Content-Type"), body)
+				}
+			}
+		}
+	}
+}
+
+func TestRoutesInvalidSpec(t *testing.T) {
+	if _, err := Routes([]byte("swagger: 2.0")); err == nil {
+		t.Error("Routes accepted a yaml document")
+	}
+}
+
+func TestSwaggerUI(t *testing.T) {
+	dist := fstest.MapFS{
+		"swagger-ui.css":                  {Data: []byte("css")},
+		"swagger-ui-bundle.js":            {Data: []byte("bundle")},
+		"swagger-ui-standalone-preset.js": {Data: []byte("preset")},
+		"index.html":                      {Data: []byte("unused")},
+	}
+
+	tests := []struct {
+		name   string
+		opts   []Option
+		paths  []string
+		assets string
+		spec   string
+	}{
+		{"dist", []Option{WithSwaggerUI(dist)}, []string{DefaultPath, DefaultSpecPath,
+			"/swagger/swagger-ui.css", "/swagger/swagger-ui-bundle.js", "/swagger/swagger-ui-standalone-preset.js"},
+			`href="/swagger/swagger-ui.css"`, `url: "/swagger-json"`},
+		{"cdn", []Option{WithSwaggerUIURL(SwaggerUICDN + "/"), WithSpecPath("")}, []string{DefaultPath},
+			`src="` + SwaggerUICDN + `/swagger-ui-bundle.js"`, `spec: {"swagger": "2.0"`},
+	}
+	for _, tt := range tests {
+		routes, err := Routes([]byte(spec), tt.opts...)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		var paths []string
+		for _, route := range routes {
+			paths = append(paths, route.Path)
+		}
+		if strings.Join(paths, " ") != strings.Join(tt.paths, " ") {
+			t.Fatalf("%s: routes %v, want %v", tt.name, paths, tt.paths)
+		}
+
+		w := httptest.NewRecorder()
+		routes[0].Handler(w, httptest.NewRequest(http.MethodGet, routes[0].Path, nil))
+		body := w.Body.String()
+		for _, want := range []string{"<title>user-api &lt;/script&gt;</title>", "SwaggerUIBundle(", tt.assets, tt.spec} {
+			if !strings.Contains(body, want) {
+				t.Errorf("%s: page has no %s", tt.name, want)
+			}
+		}
+		if strings.Count(body, "</script>") != strings.Count(body, "<script") {
+			t.Errorf("%s: unbalanced script elements", tt.name)
+		}
+	}
+
+	routes, _ := Routes([]byte(spec), WithSwaggerUI(dist))
+	w := httptest.NewRecorder()
+	routes[3].Handler(w, httptest.NewRequest(http.MethodGet, routes[3].Path, nil))
+	if w.Body.String() != "bundle" || !strings.HasPrefix(w.Header().Get("Content-Type"), "text/javascript") {
+		t.Errorf("bundle %q %q", w.Header().Get("Content-Type"), w.Body.String())
+	}
+
+	if _, err := Routes([]byte(spec), WithSwaggerUI(fstest.MapFS{})); err == nil {
+		t.Error("Routes accepted a dist without the swagger-ui files")
+	}
+}
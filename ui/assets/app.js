@@ -0,0 +1,490 @@
+// A small renderer of Swagger 2.0 and OpenAPI 3.x documents, it works
+// offline and builds the page with DOM calls only, so nothing in the
+// document is interpreted as HTML.
+(function () {
+  'use strict';
+
+  var METHODS = ['get', 'put', 'post', 'delete', 'patch', 'options', 'head'];
+  var spec = JSON.parse(document.getElementById('spec').textContent);
+  var isV3 = typeof spec.openapi === 'string';
+
+  function el(tag, attrs, children) {
+    var node = document.createElement(tag);
+    Object.keys(attrs || {}).forEach(function (key) {
+      if (key === 'text') {
+        node.textContent = attrs[key];
+      } else if (key === 'className') {
+        node.className = attrs[key];
+      } else {
+        node.setAttribute(key, attrs[key]);
+      }
+    });
+    (children || []).forEach(function (child) {
+      if (child === null || child === undefined) {
+        return;
+      }
+      node.appendChild(typeof child === 'string' ? document.createTextNode(child) : child);
+    });
+    return node;
+  }
+
+  function resolve(value) {
+    for (var i = 0; i < 32 && value && typeof value.$ref === 'string' && value.$ref.indexOf('#/') === 0; i++) {
+      var node = spec;
+      value.$ref.slice(2).split('/').forEach(function (token) {
+        token = token.replace(/~1/g, '/').replace(/~0/g, '~');
+        node = node ? node[token] : undefined;
+      });
+      value = node;
+    }
+    return value;
+  }
+
+  function refName(ref) {
+    return ref.split('/').pop();
+  }
+
+  function anchor(prefix, name) {
+    return prefix + '-' + name.replace(/\s+/g, '_');
+  }
+
+  function typeOf(schema) {
+    var t = schema.type;
+    if (Array.isArray(t)) {
+      t = t.filter(function (x) { return x !== 'null'; }).join('|');
+    }
+    return t;
+  }
+
+  function typeName(schema) {
+    if (!schema) {
+      return '';
+    }
+    if (schema.$ref) {
+      return refName(schema.$ref);
+    }
+    if (schema.allOf) {
+      return schema.allOf.map(typeName).join(' & ');
+    }
+    var t = typeOf(schema);
+    if (t === 'array') {
+      return '[]' + typeName(schema.items);
+    }
+    if (schema.additionalProperties && typeof schema.additionalProperties === 'object') {
+      return 'map[string]' + typeName(schema.additionalProperties);
+    }
+    if (schema.format) {
+      return (t || '') + '(' + schema.format + ')';
+    }
+    return t || 'object';
+  }
+
+  function range(schema) {
+    var min = schema.minimum, max = schema.maximum;
+    var exMin = schema.exclusiveMinimum, exMax = schema.exclusiveMaximum;
+    // 3.1 has the exclusive bounds as numbers
+    if (typeof exMin === 'number') {
+      min = exMin;
+      exMin = true;
+    }
+    if (typeof exMax === 'number') {
+      max = exMax;
+      exMax = true;
+    }
+    if (min !== undefined && max !== undefined) {
+      return (exMin ? '(' : '[') + min + ', ' + max + (exMax ? ')' : ']');
+    }
+    if (min !== undefined) {
+      return (exMin ? '> ' : '>= ') + min;
+    }
+    if (max !== undefined) {
+      return (exMax ? '< ' : '<= ') + max;
+    }
+    return '';
+  }
+
+  function text(value) {
+    if (value === undefined || value === null) {
+      return '';
+    }
+    return typeof value === 'string' ? value : JSON.stringify(value);
+  }
+
+  // fields lists the fields of schema and of the types nested in it, a type
+  // is not expanded inside itself.
+  function fields(schema, prefix, seen, out) {
+    if (!schema) {
+      return out;
+    }
+    if (schema.$ref) {
+      var name = schema.$ref;
+      if (seen[name]) {
+        return out;
+      }
+      seen[name] = true;
+      fields(resolve(schema), prefix, seen, out);
+      delete seen[name];
+      return out;
+    }
+    (schema.allOf || []).concat(schema.anyOf || [], schema.oneOf || []).forEach(function (part) {
+      fields(part, prefix, seen, out);
+    });
+    if (typeOf(schema) === 'array') {
+      return fields(schema.items, prefix + '[]', seen, out);
+    }
+    if (schema.additionalProperties && typeof schema.additionalProperties === 'object') {
+      return fields(schema.additionalProperties, prefix + '{}', seen, out);
+    }
+    var required = schema.required || [];
+    Object.keys(schema.properties || {}).forEach(function (key) {
+      var prop = schema.properties[key];
+      var name = prefix ? prefix + '.' + key : key;
+      out.push({ name: name, schema: prop, required: required.indexOf(key) >= 0 });
+      fields(prop, name, seen, out);
+    });
+    return out;
+  }
+
+  // typed converts the examples and defaults goctl-swagger keeps as strings
+  // to the type of schema.
+  function typed(schema, value) {
+    if (typeof value !== 'string') {
+      return value;
+    }
+    switch (typeOf(schema)) {
+      case 'integer':
+      case 'number':
+        return isNaN(Number(value)) || value.trim() === '' ? value : Number(value);
+      case 'boolean':
+        return value === 'true' ? true : value === 'false' ? false : value;
+      case 'array':
+      case 'object':
+        try {
+          return JSON.parse(value);
+        } catch (e) {
+          return value;
+        }
+    }
+    return value;
+  }
+
+  function example(schema, seen) {
+    if (!schema) {
+      return null;
+    }
+    if (schema.$ref) {
+      if (seen[schema.$ref]) {
+        return null;
+      }
+      seen[schema.$ref] = true;
+      var value = example(resolve(schema), seen);
+      delete seen[schema.$ref];
+      return value;
+    }
+    if (schema.allOf) {
+      var merged = {};
+      schema.allOf.concat([{ properties: schema.properties }]).forEach(function (part) {
+        var v = example(part, seen);
+        if (v && typeof v === 'object' && !Array.isArray(v)) {
+          Object.keys(v).forEach(function (key) { merged[key] = v[key]; });
+        }
+      });
+      return merged;
+    }
+    if (schema.example !== undefined) {
+      return typed(schema, schema.example);
+    }
+    if (schema['default'] !== undefined) {
+      return typed(schema, schema['default']);
+    }
+    if (schema['enum'] && schema['enum'].length) {
+      return typed(schema, schema['enum'][0]);
+    }
+    switch (typeOf(schema)) {
+      case 'array':
+        var item = example(schema.items, seen);
+        return item === null ? [] : [item];
+      case 'integer':
+      case 'number':
+        return 0;
+      case 'boolean':
+        return false;
+      case 'string':
+        return schema.format === 'date-time' ? '2006-01-02T15:04:05Z' : 'string';
+      case 'file':
+        return '';
+    }
+    var obj = {};
+    Object.keys(schema.properties || {}).forEach(function (key) {
+      obj[key] = example(schema.properties[key], seen);
+    });
+    if (schema.additionalProperties && typeof schema.additionalProperties === 'object') {
+      obj.key = example(schema.additionalProperties, seen);
+    }
+    return obj;
+  }
+
+  function mediaSchema(content) {
+    if (!content) {
+      return null;
+    }
+    var types = Object.keys(content);
+    var type = types.indexOf('application/json') >= 0 ? 'application/json' : types[0];
+    return type ? { type: type, schema: content[type].schema } : null;
+  }
+
+  // operations returns the operations in the version independent form the
+  // page is rendered from.
+  function operations() {
+    var ret = [];
+    Object.keys(spec.paths || {}).forEach(function (path) {
+      var item = resolve(spec.paths[path]) || {};
+      METHODS.forEach(function (method) {
+        var op = item[method];
+        if (!op) {
+          return;
+        }
+        var params = [], body = null;
+        (item.parameters || []).concat(op.parameters || []).forEach(function (p) {
+          p = resolve(p);
+          if (p.in === 'body') {
+            body = { schema: p.schema, description: p.description };
+          } else {
+            params.push(p);
+          }
+        });
+        if (op.requestBody) {
+          var requestBody = resolve(op.requestBody);
+          var media = mediaSchema(requestBody.content);
+          if (media) {
+            body = { schema: media.schema, description: requestBody.description, type: media.type };
+          }
+        }
+        var responses = [];
+        Object.keys(op.responses || {}).sort().forEach(function (code) {
+          var resp = resolve(op.responses[code]) || {};
+          var schema = resp.schema;
+          if (!schema && resp.content) {
+            var m = mediaSchema(resp.content);
+            schema = m && m.schema;
+          }
+          responses.push({ code: code, description: resp.description || '', schema: schema });
+        });
+        ret.push({
+          id: op.operationId || method + path,
+          method: method,
+          path: path,
+          // swtags: "tag" keeps its quotes
+          tag: ((op.tags && op.tags[0]) || 'default').replace(/^"|"$/g, ''),
+          op: op,
+          params: params,
+          body: body,
+          responses: responses
+        });
+      });
+    });
+    return ret;
+  }
+
+  function table(header, rows) {
+    return el('table', {}, [
+      el('thead', {}, [el('tr', {}, header.map(function (h) { return el('th', { text: h }); }))]),
+      el('tbody', {}, rows.map(function (row) {
+        return el('tr', {}, row.map(function (cell, i) {
+          return el('td', i === 0 ? { className: 'name' } : {}, [cell]);
+        }));
+      }))
+    ]);
+  }
+
+  function typeCell(schema) {
+    var name = typeName(schema);
+    var target = schema && (schema.$ref || (schema.items && schema.items.$ref) ||
+      (schema.additionalProperties && schema.additionalProperties.$ref));
+    if (target) {
+      return el('a', { href: '#' + anchor('model', refName(target)), text: name });
+    }
+    return el('code', { text: name });
+  }
+
+  function requiredCell(required) {
+    return el('span', required ? { className: 'required', text: 'yes' } : { text: 'no' });
+  }
+
+  function fieldTable(schema) {
+    var rows = fields(schema, '', {}, []).map(function (f) {
+      var s = f.schema || {};
+      return [
+        el('code', { text: f.name }),
+        typeCell(s),
+        requiredCell(f.required),
+        text((s['enum'] || []).join(', ')),
+        range(s),
+        text(s['default']),
+        text(s.example),
+        text(s.description)
+      ];
+    });
+    if (!rows.length) {
+      return null;
+    }
+    return table(['Field', 'Type', 'Required', 'Enum', 'Range', 'Default', 'Example', 'Description'], rows);
+  }
+
+  function exampleBlock(schema) {
+    return el('details', {}, [
+      el('summary', { text: 'Example' }),
+      el('pre', { text: JSON.stringify(example(schema, {}), null, 2) })
+    ]);
+  }
+
+  function renderOperation(o) {
+    var op = o.op;
+    var section = el('section', { id: anchor('op', o.id), className: 'operation' + (op.deprecated ? ' deprecated' : '') }, [
+      el('h3', {}, [
+        el('span', { className: 'method ' + o.method, text: o.method }),
+        el('span', { className: 'path', text: o.path }),
+        el('span', { className: 'summary', text: op.summary || '' }),
+        op.deprecated ? el('span', { className: 'badge', text: 'deprecated' }) : null
+      ])
+    ]);
+    if (op.description) {
+      section.appendChild(el('p', { text: op.description }));
+    }
+    var security = op.security || spec.security;
+    if (security && security.length) {
+      section.appendChild(el('p', {}, ['Authorization: ', el('code', { text: Object.keys(security[0]).join(', ') })]));
+    }
+
+    if (o.params.length) {
+      section.appendChild(el('h4', { text: 'Parameters' }));
+      section.appendChild(table(['Name', 'In', 'Type', 'Required', 'Enum', 'Range', 'Default', 'Example', 'Description'],
+        o.params.map(function (p) {
+          var s = p.schema || p;
+          return [
+            el('code', { text: p.name }),
+            p.in,
+            typeCell(s),
+            requiredCell(p.required),
+            text((s['enum'] || []).join(', ')),
+            range(s),
+            text(s['default']),
+            text(p.example !== undefined ? p.example : s.example),
+            text(p.description)
+          ];
+        })));
+    }
+
+    if (o.body && o.body.schema) {
+      section.appendChild(el('h4', {}, ['Request body ', typeCell(o.body.schema),
+        o.body.type ? el('span', { className: 'badge', text: o.body.type }) : null]));
+      if (o.body.description) {
+        section.appendChild(el('p', { text: o.body.description }));
+      }
+      section.appendChild(fieldTable(o.body.schema) || el('span'));
+      section.appendChild(exampleBlock(o.body.schema));
+    }
+
+    section.appendChild(el('h4', { text: 'Responses' }));
+    section.appendChild(table(['Code', 'Type', 'Description'], o.responses.map(function (r) {
+      return [el('code', { text: r.code }), r.schema ? typeCell(r.schema) : '', r.description];
+    })));
+    o.responses.forEach(function (r) {
+      var fieldsOf = r.schema && fieldTable(r.schema);
+      if (fieldsOf) {
+        section.appendChild(el('h4', {}, ['Response ' + r.code + ' ', typeCell(r.schema)]));
+        section.appendChild(fieldsOf);
+        section.appendChild(exampleBlock(r.schema));
+      }
+    });
+    return section;
+  }
+
+  function render() {
+    var info = spec.info || {};
+    var ops = operations();
+    var tags = [];
+    var byTag = {};
+    ops.forEach(function (o) {
+      if (!byTag[o.tag]) {
+        byTag[o.tag] = [];
+        tags.push(o.tag);
+      }
+      byTag[o.tag].push(o);
+    });
+
+    var search = el('input', { type: 'search', placeholder: 'Filter' });
+    var nav = el('nav', { id: 'sidebar' }, [search]);
+    var content = el('main', { id: 'content' }, [el('h1', { text: info.title || document.title })]);
+    if (info.version) {
+      content.appendChild(el('p', {}, ['Version ', el('code', { text: info.version })]));
+    }
+    if (info.description) {
+      content.appendChild(el('p', { text: info.description }));
+    }
+    var server = isV3 ? (spec.servers && spec.servers[0] && spec.servers[0].url) : (spec.host || '') + (spec.basePath || '');
+    if (server) {
+      content.appendChild(el('p', {}, ['Base URL ', el('code', { text: server })]));
+    }
+
+    var entries = [];
+    tags.forEach(function (tag) {
+      var list = el('ul');
+      var heading = el('h3', { text: tag });
+      nav.appendChild(heading);
+      nav.appendChild(list);
+      content.appendChild(el('h2', { id: anchor('tag', tag), className: 'tag', text: tag }));
+      byTag[tag].forEach(function (o) {
+        var link = el('a', { href: '#' + anchor('op', o.id), title: o.path }, [
+          el('span', { className: 'method ' + o.method, text: o.method }),
+          el('span', { text: o.op.summary || o.path })
+        ]);
+        var entry = el('li', {}, [link]);
+        list.appendChild(entry);
+        entries.push({ entry: entry, heading: heading, list: list, text: (tag + ' ' + o.path + ' ' + (o.op.summary || '') + ' ' + o.id).toLowerCase() });
+        content.appendChild(renderOperation(o));
+      });
+    });
+
+    var models = isV3 ? (spec.components && spec.components.schemas) : spec.definitions;
+    var modelRef = isV3 ? '#/components/schemas/' : '#/definitions/';
+    var names = Object.keys(models || {}).sort();
+    if (names.length) {
+      nav.appendChild(el('h3', {}, [el('a', { href: '#models', text: 'Models' })]));
+      content.appendChild(el('h2', { id: 'models', className: 'tag', text: 'Models' }));
+      names.forEach(function (name) {
+        var schema = models[name];
+        var section = el('section', { id: anchor('model', name), className: 'operation' }, [el('h3', { text: name })]);
+        if (schema.description) {
+          section.appendChild(el('p', { text: schema.description }));
+        }
+        section.appendChild(fieldTable({ $ref: modelRef + name.replace(/~/g, '~0').replace(/\//g, '~1') }) ||
+          el('p', {}, [el('code', { text: typeName(schema) })]));
+        content.appendChild(section);
+      });
+    }
+
+    search.addEventListener('input', function () {
+      var q = search.value.trim().toLowerCase();
+      entries.forEach(function (e) {
+        e.entry.classList.toggle('hidden', q !== '' && e.text.indexOf(q) < 0);
+      });
+      entries.forEach(function (e) {
+        var visible = e.list.querySelectorAll('li:not(.hidden)').length > 0;
+        e.heading.classList.toggle('hidden', !visible);
+      });
+    });
+
+    var app = document.getElementById('app');
+    app.textContent = '';
+    app.appendChild(el('div', { id: 'layout' }, [nav, content]));
+    if (location.hash) {
+      var target = document.getElementById(decodeURIComponent(location.hash.slice(1)));
+      if (target) {
+        target.scrollIntoView();
+      }
+    }
+  }
+
+  render();
+})();
@@ -0,0 +1,43 @@
+// Package ui renders a Swagger 2.0 or OpenAPI 3.x document as a single HTML
+// page, the stylesheet, the script and the document are inlined so the page
+// works offline.
+package ui
+
+import (
+	"bytes"
+	"embed"
+	"html"
+	"text/template"
+)
+
+//go:embed assets
+var assets embed.FS
+
+var page = template.Must(template.ParseFS(assets, "assets/index.html"))
+
+// Page returns the HTML page documenting spec, the JSON of a Swagger 2.0 or
+// OpenAPI 3.x document.
+func Page(title string, spec []byte) ([]byte, error) {
+	style, err := assets.ReadFile("assets/style.css")
+	if err != nil {
+		return nil, err
+	}
+	script, err := assets.ReadFile("assets/app.js")
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	err = page.Execute(&buf, map[string]string{
+		"Title":  html.EscapeString(title),
+		"Style":  string(style),
+		"Script": string(script),
+		// < only occurs in json strings, escaping it keeps the document from
+		// closing the script element it is in
+		"Spec": string(bytes.ReplaceAll(spec, []byte("<"), []byte(`\u003c`))),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
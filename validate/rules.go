@@ -0,0 +1,197 @@
+package validate
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// OperationMethods are the keys of a path item holding operations.
+var OperationMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+var pathTemplatePattern = regexp.MustCompile(`\{([^{}/]+)\}`)
+
+// checkRefs reports the empty and the dangling local references.
+func checkRefs(root map[string]interface{}) []Problem {
+	var problems []Problem
+	var walk func(v interface{}, location string)
+	walk = func(v interface{}, location string) {
+		switch v := v.(type) {
+		case map[string]interface{}:
+			if ref, ok := v["$ref"].(string); ok {
+				switch {
+				case len(ref) == 0:
+					problems = append(problems, Problem{Location: location, Rule: RuleRef, Message: "empty $ref"})
+				case strings.HasPrefix(ref, "#"):
+					if _, ok := Resolve(root, ref); !ok {
+						problems = append(problems, Problem{Location: location, Rule: RuleRef, Message: fmt.Sprintf("$ref %s does not resolve", ref)})
+					}
+				}
+			}
+			for _, key := range SortedKeys(v) {
+				walk(v[key], location+"/"+escape(key))
+			}
+		case []interface{}:
+			for i, item := range v {
+				walk(item, location+"/"+strconv.Itoa(i))
+			}
+		}
+	}
+	walk(root, "#")
+	return problems
+}
+
+// checkOperationIDs reports the operationIds used by more than one operation.
+func checkOperationIDs(root map[string]interface{}) []Problem {
+	var problems []Problem
+	first := map[string]string{}
+	forEachOperation(root, func(path string, item map[string]interface{}, method string, op map[string]interface{}) {
+		id, ok := op["operationId"].(string)
+		if !ok {
+			return
+		}
+
+		location := operationLocation(path, method) + "/operationId"
+		if other, ok := first[id]; ok {
+			problems = append(problems, Problem{
+				Location: location,
+				Rule:     RuleOperationID,
+				Message:  fmt.Sprintf("operationId %q is already used by %s", id, other),
+			})
+			return
+		}
+		first[id] = operationLocation(path, method)
+	})
+	return problems
+}
+
+// checkPathParams reports the path templates and path parameters that do
+// not match, and the path parameters that are not required.
+func checkPathParams(root map[string]interface{}) []Problem {
+	var problems []Problem
+	forEachOperation(root, func(path string, item map[string]interface{}, method string, op map[string]interface{}) {
+		location := operationLocation(path, method)
+
+		declared := map[string]bool{}
+		for _, params := range []interface{}{item["parameters"], op["parameters"]} {
+			list, _ := params.([]interface{})
+			for _, param := range list {
+				p, ok := param.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				if ref, ok := p["$ref"].(string); ok {
+					resolved, _ := Resolve(root, ref)
+					if p, ok = resolved.(map[string]interface{}); !ok {
+						continue
+					}
+				}
+				if p["in"] != "path" {
+					continue
+				}
+
+				name, _ := p["name"].(string)
+				declared[name] = true
+				if required, _ := p["required"].(bool); !required {
+					problems = append(problems, Problem{
+						Location: location,
+						Rule:     RulePathParams,
+						Message:  fmt.Sprintf("path parameter %s must be required", name),
+					})
+				}
+			}
+		}
+
+		templated := map[string]bool{}
+		for _, match := range pathTemplatePattern.FindAllStringSubmatch(path, -1) {
+			templated[match[1]] = true
+			if !declared[match[1]] {
+				problems = append(problems, Problem{
+					Location: location,
+					Rule:     RulePathParams,
+					Message:  fmt.Sprintf("path template {%s} has no path parameter", match[1]),
+				})
+			}
+		}
+		for _, name := range SortedKeys(declared) {
+			if !templated[name] {
+				problems = append(problems, Problem{
+					Location: location,
+					Rule:     RulePathParams,
+					Message:  fmt.Sprintf("path parameter %s does not appear in the path template", name),
+				})
+			}
+		}
+	})
+	return problems
+}
+
+// forEachOperation calls fn for the operations of the document in path order.
+func forEachOperation(root map[string]interface{}, fn func(path string, item map[string]interface{}, method string, op map[string]interface{})) {
+	paths, _ := root["paths"].(map[string]interface{})
+	for _, path := range SortedKeys(paths) {
+		item, ok := paths[path].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for _, method := range OperationMethods {
+			if op, ok := item[method].(map[string]interface{}); ok {
+				fn(path, item, method, op)
+			}
+		}
+	}
+}
+
+func operationLocation(path, method string) string {
+	return "#/paths/" + escape(path) + "/" + method
+}
+
+// Resolve looks up the local reference ref such as #/definitions/Foo in the
+// decoded document root.
+func Resolve(root interface{}, ref string) (interface{}, bool) {
+	pointer := strings.TrimPrefix(ref, "#")
+	if len(pointer) == 0 {
+		return root, true
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, false
+	}
+
+	v := root
+	for _, token := range strings.Split(pointer[1:], "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		switch node := v.(type) {
+		case map[string]interface{}:
+			var ok bool
+			if v, ok = node[token]; !ok {
+				return nil, false
+			}
+		case []interface{}:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(node) {
+				return nil, false
+			}
+			v = node[i]
+		default:
+			return nil, false
+		}
+	}
+	return v, true
+}
+
+// escape encodes token for a JSON pointer.
+func escape(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+// SortedKeys returns the keys of m in order, for stable output.
+func SortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
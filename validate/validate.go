@@ -0,0 +1,265 @@
+// Package validate checks Swagger 2.0 and OpenAPI 3.x documents against the
+// official meta-schemas and a few semantic rules the meta-schemas cannot
+// express, such as dangling references.
+package validate
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/dyntrait/goctl-swagger/generate"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	"gopkg.in/yaml.v2"
+)
+
+// Rules reported in Problem.Rule.
+const (
+	RuleSchema      = "schema"
+	RuleVersion     = "version"
+	RuleRef         = "ref"
+	RuleOperationID = "operation-id"
+	RulePathParams  = "path-params"
+)
+
+//go:embed schemas/*.json
+var schemas embed.FS
+
+const (
+	draft4Schema = "schemas/draft-04.json"
+	draft4URL    = "http://json-schema.org/draft-04/schema"
+)
+
+// metaSchemas maps the specification version to its meta-schema in schemas.
+var metaSchemas = map[string]string{
+	generate.SpecSwagger2:  "schemas/swagger2.json",
+	generate.SpecOpenAPI3:  "schemas/openapi3.json",
+	generate.SpecOpenAPI31: "schemas/openapi31.json",
+}
+
+// Problem is a violation found in a document.
+type Problem struct {
+	// Location is the JSON pointer of the offending value, such as
+	// #/paths/~1api~1user/get/responses/200.
+	Location string `json:"location"`
+	Rule     string `json:"rule"`
+	Message  string `json:"message"`
+}
+
+func (p Problem) String() string {
+	return fmt.Sprintf("%s: %s (%s)", p.Location, p.Message, p.Rule)
+}
+
+// Error is returned by File when the document has problems.
+type Error struct {
+	Path     string
+	Problems []Problem
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %d problem(s) found", e.Path, len(e.Problems))
+}
+
+// File validates the JSON or YAML document at path, it returns an *Error
+// listing the problems of an invalid document.
+func File(path string) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return &generate.InputError{Source: path, Err: err}
+	}
+
+	problems, err := Document(data)
+	if err != nil {
+		return &generate.InputError{Source: path, Err: err}
+	}
+	if len(problems) > 0 {
+		return &Error{Path: path, Problems: problems}
+	}
+	return nil
+}
+
+// Document validates a JSON or YAML document, the error is only set when the
+// document cannot be decoded.
+func Document(data []byte) ([]Problem, error) {
+	doc, err := Decode(data)
+	if err != nil {
+		return nil, err
+	}
+
+	root, ok := doc.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("document is not an object")
+	}
+
+	version, problem := specVersion(root)
+	if problem != nil {
+		return []Problem{*problem}, nil
+	}
+
+	problems, err := validateSchema(version, doc)
+	if err != nil {
+		return nil, err
+	}
+	problems = append(problems, checkRefs(root)...)
+	problems = append(problems, checkOperationIDs(root)...)
+	problems = append(problems, checkPathParams(root)...)
+
+	sort.SliceStable(problems, func(i, j int) bool {
+		return problems[i].Location < problems[j].Location
+	})
+	return problems, nil
+}
+
+// specVersion tells the document version from its swagger or openapi field.
+func specVersion(root map[string]interface{}) (string, *Problem) {
+	if v, ok := root["swagger"].(string); ok {
+		if v == "2.0" {
+			return generate.SpecSwagger2, nil
+		}
+		return "", &Problem{Location: "#/swagger", Rule: RuleVersion, Message: fmt.Sprintf("unsupported swagger version %q", v)}
+	}
+
+	v, _ := root["openapi"].(string)
+	switch {
+	case strings.HasPrefix(v, "3.0."):
+		return generate.SpecOpenAPI3, nil
+	case strings.HasPrefix(v, "3.1."):
+		return generate.SpecOpenAPI31, nil
+	case len(v) > 0:
+		return "", &Problem{Location: "#/openapi", Rule: RuleVersion, Message: fmt.Sprintf("unsupported openapi version %q", v)}
+	default:
+		return "", &Problem{Location: "#", Rule: RuleVersion, Message: "neither swagger nor openapi version is set"}
+	}
+}
+
+func validateSchema(version string, doc interface{}) ([]Problem, error) {
+	name := metaSchemas[version]
+	data, err := schemas.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+
+	compiler := jsonschema.NewCompiler()
+	if err := compiler.AddResource(name, bytes.NewReader(data)); err != nil {
+		return nil, err
+	}
+	// the swagger 2.0 schema refers to definitions of the draft 4 meta-schema
+	draft4, err := schemas.ReadFile(draft4Schema)
+	if err != nil {
+		return nil, err
+	}
+	if err := compiler.AddResource(draft4URL, bytes.NewReader(draft4)); err != nil {
+		return nil, err
+	}
+	schema, err := compiler.Compile(name)
+	if err != nil {
+		return nil, err
+	}
+
+	err = schema.Validate(doc)
+	var validationErr *jsonschema.ValidationError
+	if !errors.As(err, &validationErr) {
+		return nil, err
+	}
+
+	// only the leaves say what is wrong, the other errors are the keywords
+	// leading to them
+	var problems []Problem
+	seen := map[Problem]bool{}
+	for _, leaf := range leaves(validationErr) {
+		location, err := url.PathUnescape(leaf.InstanceLocation)
+		if err != nil {
+			location = leaf.InstanceLocation
+		}
+		p := Problem{Location: "#" + location, Rule: RuleSchema, Message: leaf.Message}
+		if !seen[p] {
+			seen[p] = true
+			problems = append(problems, p)
+		}
+	}
+	return problems, nil
+}
+
+// leaves returns the errors without causes below e. Only the closest branch
+// of a oneOf or anyOf is followed, the one failing deepest in the document
+// with the fewest errors, as the others are usually meant for other kinds
+// of values, e.g. the body parameter schema for a path parameter.
+func leaves(e *jsonschema.ValidationError) []*jsonschema.ValidationError {
+	if len(e.Causes) == 0 {
+		return []*jsonschema.ValidationError{e}
+	}
+
+	if strings.HasSuffix(e.KeywordLocation, "/oneOf") || strings.HasSuffix(e.KeywordLocation, "/anyOf") {
+		var best []*jsonschema.ValidationError
+		bestDepth := -1
+		for _, cause := range e.Causes {
+			branch := leaves(cause)
+			depth := 0
+			for _, leaf := range branch {
+				if d := strings.Count(leaf.InstanceLocation, "/"); d > depth {
+					depth = d
+				}
+			}
+			if depth > bestDepth || (depth == bestDepth && len(branch) < len(best)) {
+				best, bestDepth = branch, depth
+			}
+		}
+		return best
+	}
+
+	var ret []*jsonschema.ValidationError
+	for _, cause := range e.Causes {
+		ret = append(ret, leaves(cause)...)
+	}
+	return ret
+}
+
+// Decode reads a JSON or YAML document into the values encoding/json
+// produces, numbers are json.Number.
+func Decode(data []byte) (interface{}, error) {
+	if !json.Valid(data) {
+		var v interface{}
+		if err := yaml.Unmarshal(data, &v); err != nil {
+			return nil, err
+		}
+		// re-encode so that both formats decode to the same values
+		var err error
+		if data, err = json.Marshal(normalizeYAML(v)); err != nil {
+			return nil, err
+		}
+	}
+
+	var doc interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// normalizeYAML turns the map[interface{}]interface{} of yaml.v2 into
+// map[string]interface{} which encoding/json can marshal.
+func normalizeYAML(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			m[fmt.Sprint(key)] = normalizeYAML(value)
+		}
+		return m
+	case []interface{}:
+		for i := range v {
+			v[i] = normalizeYAML(v[i])
+		}
+		return v
+	default:
+		return v
+	}
+}
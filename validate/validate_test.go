@@ -0,0 +1,67 @@
+package validate
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestDocument(t *testing.T) {
+	tests := []struct {
+		name  string
+		data  string
+		rules []string
+	}{
+		{"valid 2.0", `{"swagger": "2.0", "info": {"title": "t", "version": "1"}, "paths": {}}`, nil},
+		{"valid 3.0", `{"openapi": "3.0.3", "info": {"title": "t", "version": "1"}, "paths": {}}`, nil},
+		{"valid 3.1", "openapi: 3.1.0\ninfo: {title: t, version: '1'}\npaths: {}\n", nil},
+		{"unknown version", `{"swagger": "1.2", "info": {"title": "t", "version": "1"}, "paths": {}}`, []string{RuleVersion}},
+		{"no version", `{"info": {"title": "t", "version": "1"}}`, []string{RuleVersion}},
+		{"missing info", `{"swagger": "2.0", "paths": {}}`, []string{RuleSchema}},
+	}
+	for _, tt := range tests {
+		problems, err := Document([]byte(tt.data))
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		var rules []string
+		for _, p := range problems {
+			rules = append(rules, p.Rule)
+		}
+		if len(rules) != len(tt.rules) || len(rules) > 0 && rules[0] != tt.rules[0] {
+			t.Errorf("%s: problems %v, want rules %v", tt.name, problems, tt.rules)
+		}
+	}
+}
+
+func TestFile(t *testing.T) {
+	err := File("testdata/broken.yaml")
+	var validateErr *Error
+	if !errors.As(err, &validateErr) {
+		t.Fatalf("File = %v, want an *Error", err)
+	}
+
+	want := []string{
+		"#/paths/~1users/post/responses/200/schema: empty $ref (ref)",
+		"#/paths/~1users~1{id}/get: path template {id} has no path parameter (path-params)",
+		"#/paths/~1users~1{id}/get: path parameter uid does not appear in the path template (path-params)",
+		`#/paths/~1users~1{id}/get/operationId: operationId "getUser" is already used by #/paths/~1users/post (operation-id)`,
+		"#/paths/~1users~1{id}/get/responses/200/schema: $ref #/definitions/User does not resolve (ref)",
+	}
+	var got []string
+	for _, p := range validateErr.Problems {
+		got = append(got, p.String())
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("problems:\n%q\nwant:\n%q", got, want)
+	}
+}
+
+func TestDecode(t *testing.T) {
+	if _, err := Decode([]byte("{")); err == nil {
+		t.Error("Decode accepted truncated json")
+	}
+	if _, err := Decode([]byte("a: [")); err == nil {
+		t.Error("Decode accepted truncated yaml")
+	}
+}